@@ -0,0 +1,54 @@
+package testutils
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// CheckNoSharedBackingArray checks that the memory of the backing arrays of the two slices (up to their capacity)
+// do not overlap. This is useful to guard functions documented to return fresh copies.
+func CheckNoSharedBackingArray[T any](a, b []T, t testing.TB) {
+	if lo, hi, ok := sharedMemory(a, b); ok {
+		t.Helper()
+		t.Fatalf("Expected slices to not share backing array, but they share %d bytes at %#x-%#x", hi-lo, lo, hi)
+	}
+}
+
+// sharedMemory returns the address range shared by the backing arrays of a and b and true, or false if they
+// do not overlap.
+func sharedMemory[T any](a, b []T) (lo, hi uintptr, ok bool) {
+	var zero T
+	size := unsafe.Sizeof(zero)
+	if size == 0 || cap(a) == 0 || cap(b) == 0 {
+		return 0, 0, false
+	}
+	aStart := uintptr(unsafe.Pointer(&a[:cap(a)][0]))
+	bStart := uintptr(unsafe.Pointer(&b[:cap(b)][0]))
+	aEnd := aStart + uintptr(cap(a))*size
+	bEnd := bStart + uintptr(cap(b))*size
+	lo, hi = aStart, aEnd
+	if bStart > lo {
+		lo = bStart
+	}
+	if bEnd < hi {
+		hi = bEnd
+	}
+	return lo, hi, lo < hi
+}
+
+// CheckStringNotAliased checks that the string returned by convert does not share memory with the byte slice
+// given to it. This guards against unsafe []byte to string conversions that would make the string change
+// when the caller later modifies the slice. The check calls convert with a copy of the input, modifies every byte
+// of that copy, and then asserts that the returned string is unchanged.
+func CheckStringNotAliased(input []byte, convert func([]byte) string, t testing.TB) {
+	buf := append([]byte(nil), input...)
+	s := convert(buf)
+	before := string([]byte(s))
+	for i := range buf {
+		buf[i] ^= 0xff
+	}
+	if s != before {
+		t.Helper()
+		t.Fatalf("Expected string returned for %q to not be aliased to the given byte slice, but it changed to %q when the slice was modified", before, s)
+	}
+}
@@ -0,0 +1,34 @@
+package testutils
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestCheckNoSharedBackingArray(t *testing.T) {
+	a := []int{1, 2, 3, 4}
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckNoSharedBackingArray(a, append([]int(nil), a...), ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckNoSharedBackingArray(a, nil, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckNoSharedBackingArray(a, a, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckNoSharedBackingArray(a[:1], a[3:], ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckNoSharedBackingArray(a[:1:1], a[1:], ft)
+	})
+}
+
+func TestCheckStringNotAliased(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckStringNotAliased([]byte("abc"), func(b []byte) string { return string(b) }, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckStringNotAliased([]byte("abc"), func(b []byte) string { return *(*string)(unsafe.Pointer(&b)) }, ft)
+	})
+}
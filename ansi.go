@@ -0,0 +1,43 @@
+package testutils
+
+import (
+	"os"
+	"regexp"
+	"testing"
+)
+
+// ansiSequence matches CSI sequences (colors, cursor movement), OSC sequences (titles, hyperlinks), and other
+// two character escape sequences.
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+
+// StripANSI returns the given string with all ANSI escape sequences (colors, styling, cursor control) removed
+func StripANSI(s string) string {
+	return ansiSequence.ReplaceAllString(s, "")
+}
+
+// NoColorEnv is the name of a testutils specific environment variable that (when set to a non empty value)
+// turns off colors in diffs, in addition to the standard NO_COLOR variable.
+const NoColorEnv = "TESTUTILS_NOCOLOR"
+
+// stdoutIsTerminal reports if test output is written to a terminal. It is a variable to allow it to be overridden.
+var stdoutIsTerminal = func() bool {
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled returns false if the NO_COLOR or TESTUTILS_NOCOLOR environment variables are set to a non empty value,
+// or if test output is not written to a terminal (unless assumeTerminal is true). Otherwise it returns true.
+func colorEnabled(assumeTerminal bool) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv(NoColorEnv) != "" {
+		return false
+	}
+	return assumeTerminal || stdoutIsTerminal()
+}
+
+// CheckTerminalTextEqual checks that the expected and got texts are equal after all ANSI escape sequences have been
+// removed from both. This is useful when both the expected and the got text are captured terminal output, and only
+// the content, not the styling, is of interest. On failure the difference is reported as by CheckTextEqual.
+func CheckTerminalTextEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	CheckTextEqual(StripANSI(expected), StripANSI(got), t)
+}
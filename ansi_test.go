@@ -0,0 +1,45 @@
+package testutils
+
+import "testing"
+
+func TestStripANSI(t *testing.T) {
+	CheckEqual("red bold plain", StripANSI("\x1b[31mred\x1b[0m \x1b[1;4mbold\x1b[m plain"), t)
+	CheckEqual("link", StripANSI("\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\"), t)
+	CheckEqual("title", StripANSI("\x1b]0;window\x07title\x1b[2K\x1b[1G"), t)
+}
+
+func TestTester_CheckTextEqualStripANSI(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckTextEqualStripANSI("ok: done", "\x1b[32mok\x1b[0m: done")
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckTextEqualStripANSI("ok: done", "\x1b[31mfailed\x1b[0m: done")
+	})
+}
+
+func Test_colorEnabled(t *testing.T) {
+	defer func(f func() bool) { stdoutIsTerminal = f }(stdoutIsTerminal)
+	stdoutIsTerminal = func() bool { return true }
+	t.Setenv("NO_COLOR", "")
+	t.Setenv(NoColorEnv, "")
+	CheckTrue(colorEnabled(false), t)
+
+	t.Setenv("NO_COLOR", "1")
+	CheckFalse(colorEnabled(true), t)
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv(NoColorEnv, "yes")
+	CheckFalse(colorEnabled(true), t)
+
+	t.Setenv(NoColorEnv, "")
+	stdoutIsTerminal = func() bool { return false }
+	CheckFalse(colorEnabled(false), t)
+	CheckTrue(colorEnabled(true), t)
+}
+
+func TestCheckTerminalTextEqual(t *testing.T) {
+	CheckTerminalTextEqual("\x1b[1mbold\x1b[0m text", "bold \x1b[32mtext\x1b[0m", t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTerminalTextEqual("\x1b[1mbold\x1b[0m", "\x1b[1mbolder\x1b[0m", ft)
+	})
+}
@@ -0,0 +1,145 @@
+package testutils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// CheckPackageAPIStable checks that the exported API of the Go package in the given directory is unchanged by
+// comparing a listing of it with the given golden file (see CheckGolden). The listing has one entry per exported
+// constant, variable, function, type, and method of an exported type, with the signatures of functions and the
+// definitions of types (without unexported struct fields and interface methods). Test files are not included.
+// Run the tests in update mode (see UpdateGolden) to accept a changed API.
+func CheckPackageAPIStable(dir, golden string, t testing.TB) {
+	t.Helper()
+	api, err := packageAPI(dir)
+	if err != nil {
+		t.Fatalf("CheckPackageAPIStable: %s", err.Error())
+		return
+	}
+	CheckGolden(golden, api, t)
+}
+
+// packageAPI returns a sorted listing of the exported API of the package in the given directory
+func packageAPI(dir string) (string, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+	fs := token.NewFileSet()
+	pkg := ""
+	var entries []string
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		f, err := parser.ParseFile(fs, name, src, parser.SkipObjectResolution)
+		if err != nil {
+			return "", err
+		}
+		if pkg == "" {
+			pkg = f.Name.Name
+		} else if pkg != f.Name.Name {
+			return "", &os.PathError{Op: "parse", Path: dir, Err: os.ErrInvalid}
+		}
+		for _, d := range f.Decls {
+			entries = append(entries, declAPI(d)...)
+		}
+	}
+	if pkg == "" {
+		return "", &os.PathError{Op: "parse", Path: dir, Err: os.ErrNotExist}
+	}
+	sort.Strings(entries)
+	return "package " + pkg + "\n\n" + strings.Join(entries, "\n") + "\n", nil
+}
+
+// declAPI returns the API entries of the exported parts of the given declaration
+func declAPI(d ast.Decl) []string {
+	var entries []string
+	switch d := d.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return nil
+		}
+		if d.Recv != nil {
+			if _, recv := receiverType(d.Recv.List[0].Type); !ast.IsExported(recv) {
+				return nil
+			}
+			d.Recv = &ast.FieldList{List: []*ast.Field{{Type: d.Recv.List[0].Type}}}
+		}
+		entries = append(entries, apiText(&ast.FuncDecl{Recv: d.Recv, Name: d.Name, Type: d.Type}))
+	case *ast.GenDecl:
+		for _, s := range d.Specs {
+			switch s := s.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					entries = append(entries, "type "+apiText(&ast.TypeSpec{
+						Name: s.Name, TypeParams: s.TypeParams, Assign: s.Assign, Type: exportedType(s.Type)}))
+				}
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if !n.IsExported() {
+						continue
+					}
+					entry := d.Tok.String() + " " + n.Name
+					if s.Type != nil {
+						entry += " " + apiText(s.Type)
+					}
+					entries = append(entries, entry)
+				}
+			}
+		}
+	}
+	return entries
+}
+
+// exportedType returns the type expression with unexported fields and methods of a struct or interface removed
+func exportedType(x ast.Expr) ast.Expr {
+	switch x := x.(type) {
+	case *ast.StructType:
+		return &ast.StructType{Fields: exportedFields(x.Fields)}
+	case *ast.InterfaceType:
+		return &ast.InterfaceType{Methods: exportedFields(x.Methods)}
+	}
+	return x
+}
+
+// exportedFields returns the exported fields (or methods) in the list, without their comments
+func exportedFields(fields *ast.FieldList) *ast.FieldList {
+	exported := &ast.FieldList{}
+	for _, f := range fields.List {
+		if len(f.Names) == 0 {
+			// embedded type or type constraint
+			if _, name := receiverType(f.Type); name == "" || ast.IsExported(name) {
+				exported.List = append(exported.List, &ast.Field{Type: f.Type, Tag: f.Tag})
+			}
+			continue
+		}
+		var names []*ast.Ident
+		for _, n := range f.Names {
+			if n.IsExported() {
+				names = append(names, n)
+			}
+		}
+		if len(names) > 0 {
+			exported.List = append(exported.List, &ast.Field{Names: names, Type: f.Type, Tag: f.Tag})
+		}
+	}
+	return exported
+}
+
+// apiText returns the source of the node formatted without comments. A new file set is used so that the layout
+// does not depend on the original positions of the nodes.
+func apiText(n ast.Node) string {
+	return nodeText(token.NewFileSet(), n)
+}
@@ -0,0 +1,20 @@
+package testutils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckPackageAPIStable(t *testing.T) {
+	golden := filepath.Join("testdata", "api.golden")
+	CheckPackageAPIStable(filepath.Join("testdata", "api"), golden, t)
+	if UpdateGolden() {
+		return
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPackageAPIStable(".", golden, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPackageAPIStable(filepath.Join("testdata", "missing"), golden, ft)
+	})
+}
@@ -0,0 +1,222 @@
+package testutils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// archiveEntry is a file or directory in an archive
+type archiveEntry struct {
+	mode    fs.FileMode
+	content []byte
+}
+
+// CheckZipContains checks that the zip file contains the expected entries. The expected entries are keyed by their
+// path in the archive, and a path that ends with '/' is a directory. The value of a file entry is its content given
+// as a string or a []byte, or a FixtureFile to also check its permission bits (they are not checked when the Mode
+// is 0). Other entries in the archive are ignored. On failure all missing and different entries are reported.
+func CheckZipContains(filename string, expected map[string]interface{}, t testing.TB) {
+	t.Helper()
+	e, err := expectedEntries(expected)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	g, err := zipEntries(filename)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if problems := archiveDiff(e, g, true); len(problems) > 0 {
+		t.Fatalf("Expected zip file %q to contain the entries, but:\n%s", filename, strings.Join(problems, "\n"))
+	}
+}
+
+// CheckZipEqual checks that the two zip files have the same entries, with the same names, permission bits, and
+// content. The order of the entries in the archives does not matter. On failure all missing, extra, and different
+// entries are reported.
+func CheckZipEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	e, err := zipEntries(expected)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	g, err := zipEntries(got)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if problems := archiveDiff(e, g, false); len(problems) > 0 {
+		t.Fatalf("Expected zip file %q to equal %q, but:\n%s", got, expected, strings.Join(problems, "\n"))
+	}
+}
+
+// CheckTarEqual checks that the two tar files, which may be compressed with gzip, have the same entries, with the
+// same names, permission bits, and content. The order of the entries in the archives does not matter. On failure
+// all missing, extra, and different entries are reported.
+func CheckTarEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	e, err := tarEntries(expected)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	g, err := tarEntries(got)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if problems := archiveDiff(e, g, false); len(problems) > 0 {
+		t.Fatalf("Expected tar file %q to equal %q, but:\n%s", got, expected, strings.Join(problems, "\n"))
+	}
+}
+
+// archiveDiff returns a description of each difference between the expected and got entries in path order. Extra
+// entries are not reported when subset is true, and permission bits are not compared when they are not set in the
+// expected entry.
+func archiveDiff(expected, got map[string]archiveEntry, subset bool) []string {
+	var problems []string
+	for _, name := range sortedEntryNames(expected) {
+		e := expected[name]
+		g, ok := got[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("missing %s %s", entryKind(e), name))
+		case e.mode.IsDir() != g.mode.IsDir():
+			problems = append(problems, fmt.Sprintf("expected %s to be a %s, got a %s", name, entryKind(e), entryKind(g)))
+		default:
+			if e.mode.Perm() != 0 && e.mode.Perm() != g.mode.Perm() {
+				problems = append(problems, fmt.Sprintf("expected mode of %s to be %s, got %s", name, e.mode.Perm(), g.mode.Perm()))
+			}
+			if !e.mode.IsDir() && !bytes.Equal(e.content, g.content) {
+				problems = append(problems, fmt.Sprintf("content of %s differs:\n%s", name, contentDiff(e.content, g.content)))
+			}
+		}
+	}
+	if !subset {
+		for _, name := range sortedEntryNames(got) {
+			if _, ok := expected[name]; !ok {
+				problems = append(problems, fmt.Sprintf("extra %s %s", entryKind(got[name]), name))
+			}
+		}
+	}
+	return problems
+}
+
+// expectedEntries converts the expected entries given to CheckZipContains to archive entries
+func expectedEntries(files map[string]interface{}) (map[string]archiveEntry, error) {
+	entries := make(map[string]archiveEntry, len(files))
+	for name, v := range files {
+		if strings.HasSuffix(name, "/") {
+			entries[entryName(name)] = archiveEntry{mode: fs.ModeDir}
+			continue
+		}
+		var e archiveEntry
+		switch v := v.(type) {
+		case string:
+			e.content = []byte(v)
+		case []byte:
+			e.content = v
+		case FixtureFile:
+			e.content, e.mode = v.Content, v.Mode.Perm()
+		default:
+			return nil, fmt.Errorf("content of %s must be a string, []byte, or FixtureFile, got %T", name, v)
+		}
+		entries[entryName(name)] = e
+	}
+	return entries, nil
+}
+
+func zipEntries(filename string) (map[string]archiveEntry, error) {
+	r, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	entries := make(map[string]archiveEntry, len(r.File))
+	for _, f := range r.File {
+		e := archiveEntry{mode: f.Mode()}
+		if !e.mode.IsDir() {
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			e.content, err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s in %s: %w", f.Name, filename, err)
+			}
+		}
+		entries[entryName(f.Name)] = e
+	}
+	return entries, nil
+}
+
+func tarEntries(filename string) (map[string]archiveEntry, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = bufio.NewReader(f)
+	if magic, _ := r.(*bufio.Reader).Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	entries := map[string]archiveEntry{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", filename, err)
+		}
+		e := archiveEntry{mode: hdr.FileInfo().Mode()}
+		if !e.mode.IsDir() {
+			if e.content, err = io.ReadAll(tr); err != nil {
+				return nil, fmt.Errorf("unable to read %s in %s: %w", hdr.Name, filename, err)
+			}
+		}
+		entries[entryName(hdr.Name)] = e
+	}
+}
+
+// entryName returns the name of an archive entry without a leading "./" and a trailing "/"
+func entryName(name string) string {
+	return path.Clean(strings.TrimPrefix(name, "./"))
+}
+
+func sortedEntryNames(m map[string]archiveEntry) []string {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func entryKind(e archiveEntry) string {
+	if e.mode.IsDir() {
+		return "directory"
+	}
+	return "file"
+}
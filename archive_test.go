@@ -0,0 +1,152 @@
+package testutils
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// writeTestArchive writes a zip file, or a tar file (gzip compressed if the name ends with .gz), with the given
+// entries. A name that ends with '/' is a directory.
+func writeTestArchive(t *testing.T, filename string, entries map[string]FixtureFile) string {
+	t.Helper()
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	names := make([]string, 0, len(entries))
+	for n := range entries {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var w io.Writer = f
+	if strings.HasSuffix(filename, ".gz") {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		w = gz
+	}
+	if strings.HasSuffix(filename, ".zip") {
+		zw := zip.NewWriter(w)
+		defer zw.Close()
+		for _, n := range names {
+			hdr := &zip.FileHeader{Name: n}
+			mode := entries[n].Mode
+			if strings.HasSuffix(n, "/") {
+				mode |= os.ModeDir
+			}
+			hdr.SetMode(mode)
+			fw, err := zw.CreateHeader(hdr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err = fw.Write(entries[n].Content); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return filename
+	}
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+	for _, n := range names {
+		e := entries[n]
+		hdr := &tar.Header{Name: n, Mode: int64(e.Mode), Size: int64(len(e.Content)), Typeflag: tar.TypeReg}
+		if strings.HasSuffix(n, "/") {
+			hdr.Typeflag = tar.TypeDir
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(e.Content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filename
+}
+
+func TestCheckZipContains(t *testing.T) {
+	dir := t.TempDir()
+	z := writeTestArchive(t, filepath.Join(dir, "a.zip"), map[string]FixtureFile{
+		"bin/":      {Mode: 0o755},
+		"bin/run":   {Content: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"README.md": {Content: []byte("hello"), Mode: 0o644},
+	})
+	CheckZipContains(z, map[string]interface{}{
+		"bin/":      nil,
+		"bin/run":   FixtureFile{Content: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"README.md": "hello",
+	}, t)
+
+	m := &messageTB{}
+	CheckZipContains(z, map[string]interface{}{
+		"bin":       "x",
+		"bin/run":   FixtureFile{Content: []byte("#!/bin/sh\n"), Mode: 0o644},
+		"README.md": "hi",
+		"LICENSE":   "",
+	}, m)
+	CheckEqual([]string{`Expected zip file "` + z + `" to contain the entries, but:
+missing file LICENSE
+content of README.md differs:
+--- expected
++++ got
+@@ -1 +1 @@
+-hi
++hello
+expected bin to be a file, got a directory
+expected mode of bin/run to be -rw-r--r--, got -rwxr-xr-x`}, m.messages, t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckZipContains(filepath.Join(dir, "missing.zip"), map[string]interface{}{}, ft)
+	})
+}
+
+func TestCheckZipEqual(t *testing.T) {
+	dir := t.TempDir()
+	entries := map[string]FixtureFile{
+		"a.txt":     {Content: []byte("a"), Mode: 0o644},
+		"sub/b.txt": {Content: []byte("b"), Mode: 0o600},
+	}
+	z1 := writeTestArchive(t, filepath.Join(dir, "1.zip"), entries)
+	z2 := writeTestArchive(t, filepath.Join(dir, "2.zip"), entries)
+	CheckZipEqual(z1, z2, t)
+
+	z3 := writeTestArchive(t, filepath.Join(dir, "3.zip"), map[string]FixtureFile{
+		"a.txt": {Content: []byte("a"), Mode: 0o644},
+		"c.txt": {Content: []byte("c"), Mode: 0o644},
+	})
+	m := &messageTB{}
+	CheckZipEqual(z1, z3, m)
+	CheckEqual([]string{`Expected zip file "` + z3 + `" to equal "` + z1 + `", but:
+missing file sub/b.txt
+extra file c.txt`}, m.messages, t)
+}
+
+func TestCheckTarEqual(t *testing.T) {
+	dir := t.TempDir()
+	entries := map[string]FixtureFile{
+		"./pkg/":     {Mode: 0o755},
+		"pkg/a.txt":  {Content: []byte("a"), Mode: 0o644},
+		"pkg/run.sh": {Content: []byte("run"), Mode: 0o755},
+	}
+	plain := writeTestArchive(t, filepath.Join(dir, "a.tar"), entries)
+	compressed := writeTestArchive(t, filepath.Join(dir, "a.tar.gz"), entries)
+	CheckTarEqual(plain, compressed, t)
+
+	entries["pkg/run.sh"] = FixtureFile{Content: []byte("run"), Mode: 0o644}
+	changed := writeTestArchive(t, filepath.Join(dir, "b.tar"), entries)
+	m := &messageTB{}
+	CheckTarEqual(plain, changed, m)
+	CheckEqual([]string{`Expected tar file "` + changed + `" to equal "` + plain + `", but:
+expected mode of pkg/run.sh to be -rwxr-xr-x, got -rw-r--r--`}, m.messages, t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTarEqual(plain, filepath.Join(dir, "missing.tar"), ft)
+	})
+}
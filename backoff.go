@@ -0,0 +1,43 @@
+package testutils
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Backoff describes a retry policy that produces successive delays. The method name is the same as in
+// commonly used backoff packages which makes it possible to pass such policies directly to CheckBackoffSequence.
+type Backoff interface {
+	NextBackOff() time.Duration
+}
+
+// BackoffFunc is an adapter that makes a function usable as a Backoff
+type BackoffFunc func() time.Duration
+
+// NextBackOff returns the result of calling f
+func (f BackoffFunc) NextBackOff() time.Duration {
+	return f()
+}
+
+// CheckBackoffSequence pulls as many delays from the given backoff policy as there are expected delays and checks
+// that each delay is within the jitter tolerance of the expected delay. The tolerance is a fraction of the expected
+// delay, i.e. 0.1 allows each delay to deviate 10% from the expected value. A tolerance of 0 requires exact values.
+func CheckBackoffSequence(b Backoff, expected []time.Duration, jitter float64, t testing.TB) {
+	got := make([]time.Duration, len(expected))
+	for i := range got {
+		got[i] = b.NextBackOff()
+	}
+	for i, e := range expected {
+		allowed := time.Duration(math.Abs(float64(e) * jitter))
+		diff := got[i] - e
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > allowed {
+			t.Helper()
+			t.Fatalf("Expected delay [%d] to be %v (±%v), got %v. Expected sequence %v, got %v", i, e, allowed, got[i], expected, got)
+			return
+		}
+	}
+}
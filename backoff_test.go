@@ -0,0 +1,29 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+func doubling(start time.Duration) BackoffFunc {
+	d := start / 2
+	return func() time.Duration {
+		d *= 2
+		return d
+	}
+}
+
+func TestCheckBackoffSequence(t *testing.T) {
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckBackoffSequence(doubling(100*time.Millisecond), expected, 0, ft)
+	})
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckBackoffSequence(doubling(105*time.Millisecond), expected, 0.1, ft)
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckBackoffSequence(doubling(120*time.Millisecond), expected, 0.1, ft)
+	})
+}
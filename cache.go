@@ -0,0 +1,114 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+// Cache describes the get/put interface of a cache implementation checked by CheckCacheBehavior
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Put(key K, value V)
+}
+
+// CacheSpec describes how to create and drive a cache implementation for CheckCacheBehavior. The New, Key and
+// Value functions are required. The TTL checks are only performed if TTL and Advance are set, and the eviction
+// checks are only performed if Capacity is greater than zero.
+type CacheSpec[K comparable, V any] struct {
+	// New returns a new empty cache
+	New func() Cache[K, V]
+
+	// Key returns the i'th key. Different values of i must produce different keys.
+	Key func(i int) K
+
+	// Value returns the value to store for the i'th key
+	Value func(i int) V
+
+	// TTL is the time to live of an entry in a cache returned from New
+	TTL time.Duration
+
+	// Advance moves the clock used by the cache forward, typically by advancing a fake clock
+	Advance func(d time.Duration)
+
+	// Capacity is the max number of entries a cache returned from New will hold
+	Capacity int
+}
+
+// CheckCacheBehavior runs the CacheContract for the given spec as subtests of t.
+func CheckCacheBehavior[K comparable, V any](spec CacheSpec[K, V], t *testing.T) {
+	t.Helper()
+	if spec.New == nil || spec.Key == nil || spec.Value == nil {
+		t.Fatalf("CheckCacheBehavior: the New, Key, and Value functions of the CacheSpec must be set")
+		return
+	}
+	RunContract(CacheContract(spec), spec.New, t)
+}
+
+// CacheContract returns the contract for a cache described by the given spec. The contract asserts that:
+//
+//   - a get after a put is a hit that returns the put value
+//   - a get for a key that was never put is a miss
+//   - a put replaces the value of an existing key
+//   - a get after the TTL has passed is a miss (if TTL and Advance are set)
+//   - no more than Capacity entries are held and the most recent put is kept (if Capacity is set)
+func CacheContract[K comparable, V any](spec CacheSpec[K, V]) Contract[Cache[K, V]] {
+	cases := []ContractCase[Cache[K, V]]{
+		{Name: "hit after put", Check: func(tt Tester, c Cache[K, V]) {
+			c.Put(spec.Key(0), spec.Value(0))
+			checkCacheHit(c, spec.Key(0), spec.Value(0), tt)
+		}},
+		{Name: "miss for unknown key", Check: func(tt Tester, c Cache[K, V]) {
+			c.Put(spec.Key(0), spec.Value(0))
+			checkCacheMiss(c, spec.Key(1), tt)
+		}},
+		{Name: "put replaces value", Check: func(tt Tester, c Cache[K, V]) {
+			c.Put(spec.Key(0), spec.Value(0))
+			c.Put(spec.Key(0), spec.Value(1))
+			checkCacheHit(c, spec.Key(0), spec.Value(1), tt)
+		}},
+	}
+
+	if spec.TTL > 0 && spec.Advance != nil {
+		cases = append(cases, ContractCase[Cache[K, V]]{Name: "miss after ttl expiry", Check: func(tt Tester, c Cache[K, V]) {
+			c.Put(spec.Key(0), spec.Value(0))
+			checkCacheHit(c, spec.Key(0), spec.Value(0), tt)
+			spec.Advance(spec.TTL + time.Nanosecond)
+			checkCacheMiss(c, spec.Key(0), tt)
+		}})
+	}
+
+	if spec.Capacity > 0 {
+		cases = append(cases, ContractCase[Cache[K, V]]{Name: "eviction under capacity", Check: func(tt Tester, c Cache[K, V]) {
+			n := spec.Capacity + 1
+			for i := 0; i < n; i++ {
+				c.Put(spec.Key(i), spec.Value(i))
+			}
+			checkCacheHit(c, spec.Key(n-1), spec.Value(n-1), tt)
+			held := 0
+			for i := 0; i < n; i++ {
+				if _, ok := c.Get(spec.Key(i)); ok {
+					held++
+				}
+			}
+			if held > spec.Capacity {
+				tt.Fatalf("Expected at most %d entries in cache with capacity %d, got %d", spec.Capacity, spec.Capacity, held)
+			}
+		}})
+	}
+	return Contract[Cache[K, V]]{Name: "cache", Cases: cases}
+}
+
+func checkCacheHit[K comparable, V any](c Cache[K, V], key K, expected V, tt Tester) {
+	got, ok := c.Get(key)
+	if !ok {
+		tt.Fatalf("Expected cache hit for key %v, got miss", key)
+		return
+	}
+	tt.CheckEqual(expected, got)
+}
+
+func checkCacheMiss[K comparable, V any](c Cache[K, V], key K, tt Tester) {
+	if got, ok := c.Get(key); ok {
+		tt.Fatalf("Expected cache miss for key %v, got hit with value %v", key, got)
+	}
+}
@@ -0,0 +1,68 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+type testCacheEntry struct {
+	value   int
+	expires time.Time
+}
+
+// testCache is a FIFO cache with ttl
+type testCache struct {
+	now      *time.Time
+	ttl      time.Duration
+	capacity int
+	order    []string
+	entries  map[string]testCacheEntry
+}
+
+func (c *testCache) Get(key string) (int, bool) {
+	e, ok := c.entries[key]
+	if !ok || c.now.After(e.expires) {
+		return 0, false
+	}
+	return e.value, true
+}
+
+func (c *testCache) Put(key string, value int) {
+	if _, ok := c.entries[key]; !ok {
+		c.order = append(c.order, key)
+		if len(c.order) > c.capacity {
+			delete(c.entries, c.order[0])
+			c.order = c.order[1:]
+		}
+	}
+	c.entries[key] = testCacheEntry{value: value, expires: c.now.Add(c.ttl)}
+}
+
+func TestCheckCacheBehavior(t *testing.T) {
+	now := time.Now()
+	CheckCacheBehavior(CacheSpec[string, int]{
+		New: func() Cache[string, int] {
+			return &testCache{now: &now, ttl: time.Minute, capacity: 3, entries: map[string]testCacheEntry{}}
+		},
+		Key:      func(i int) string { return string(rune('a' + i)) },
+		Value:    func(i int) int { return i },
+		TTL:      time.Minute,
+		Advance:  func(d time.Duration) { now = now.Add(d) },
+		Capacity: 3,
+	}, t)
+}
+
+func Test_checkCacheHitAndMiss(t *testing.T) {
+	now := time.Now()
+	c := &testCache{now: &now, ttl: time.Minute, capacity: 3, entries: map[string]testCacheEntry{}}
+	c.Put("a", 1)
+	ensureFailed(t, func(ft *testing.T) {
+		checkCacheHit[string, int](c, "b", 1, NewTester(ft))
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		checkCacheHit[string, int](c, "a", 2, NewTester(ft))
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		checkCacheMiss[string, int](c, "a", NewTester(ft))
+	})
+}
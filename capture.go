@@ -0,0 +1,215 @@
+package testutils
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// CaptureFailure calls f with a RecordingTB wrapping t, and returns whether f failed and the failure messages
+// (joined with newlines). A call to Fatal, Fatalf, FailNow, or Skip stops f, but not the test. This makes it
+// possible to assert on the exact failure messages of custom checks:
+//
+//	failed, msg := CaptureFailure(func(tb testing.TB) { CheckPositive(-1, tb) }, t)
+//	CheckTrue(failed, t)
+//	CheckEqual("Expected a positive number, got -1", msg, t)
+func CaptureFailure(f func(tb testing.TB), t testing.TB) (failed bool, message string) {
+	r := NewRecordingTB(t)
+	r.Run(f)
+	return r.Failed(), strings.Join(r.Failures(), "\n")
+}
+
+// RecordKind is the kind of a call recorded by a RecordingTB
+type RecordKind int
+
+const (
+	// RecordLog is a call to Log or Logf
+	RecordLog RecordKind = iota
+	// RecordError is a call to Error or Errorf
+	RecordError
+	// RecordFatal is a call to Fatal or Fatalf
+	RecordFatal
+	// RecordSkip is a call to Skip or Skipf
+	RecordSkip
+)
+
+// Record is a call recorded by a RecordingTB
+type Record struct {
+	Kind    RecordKind
+	Message string
+}
+
+// RecordingTB is a testing.TB that records calls to Log, Error, Fatal, and Skip (and their formatting variants)
+// and the failed and skipped states instead of reporting them. Calls to the methods it does not record (such as
+// TempDir, Setenv, and Cleanup) are made to the wrapped testing.TB, which must then not be nil.
+//
+// Fatal, Fatalf, FailNow, Skip, Skipf, and SkipNow call runtime.Goexit, just like the methods of testing.T. Use
+// Run to call code that may call them, to contain the exit. A RecordingTB is safe for use by multiple goroutines.
+type RecordingTB struct {
+	testing.TB
+	lock    sync.Mutex
+	records []Record
+	failed  bool
+	skipped bool
+}
+
+// NewRecordingTB returns a new RecordingTB wrapping the given testing.TB (which may be nil)
+func NewRecordingTB(t testing.TB) *RecordingTB {
+	return &RecordingTB{TB: t}
+}
+
+// Run calls f with the recording TB in a new goroutine and waits for f to return or exit. A panic in f is
+// recovered and recorded as a call to Fatal with the message "panic: " followed by the panic value.
+func (r *RecordingTB) Run(f func(tb testing.TB)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if p := recover(); p != nil {
+				r.record(RecordFatal, fmt.Sprintf("panic: %v", p))
+			}
+		}()
+		f(r)
+	}()
+	<-done
+}
+
+// Records returns all recorded calls in the order they were made
+func (r *RecordingTB) Records() []Record {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]Record{}, r.records...)
+}
+
+// Failures returns the messages of all calls to Error and Fatal (and their formatting variants)
+func (r *RecordingTB) Failures() []string {
+	return r.messages(RecordError, RecordFatal)
+}
+
+// Logs returns the messages of all calls to Log and Logf
+func (r *RecordingTB) Logs() []string {
+	return r.messages(RecordLog)
+}
+
+func (r *RecordingTB) messages(kinds ...RecordKind) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var messages []string
+	for _, rec := range r.records {
+		for _, k := range kinds {
+			if rec.Kind == k {
+				messages = append(messages, rec.Message)
+			}
+		}
+	}
+	return messages
+}
+
+// Name returns the name of the wrapped testing.TB, or "RecordingTB" if it is nil
+func (r *RecordingTB) Name() string {
+	if r.TB == nil {
+		return "RecordingTB"
+	}
+	return r.TB.Name()
+}
+
+// Helper does nothing
+func (r *RecordingTB) Helper() {}
+
+// Log records a log message formatted as by fmt.Sprintln (without the final newline)
+func (r *RecordingTB) Log(args ...interface{}) {
+	r.record(RecordLog, sprintln(args...))
+}
+
+// Logf records a log message formatted as by fmt.Sprintf
+func (r *RecordingTB) Logf(format string, args ...interface{}) {
+	r.record(RecordLog, fmt.Sprintf(format, args...))
+}
+
+// Fail marks the recording TB as failed
+func (r *RecordingTB) Fail() {
+	r.lock.Lock()
+	r.failed = true
+	r.lock.Unlock()
+}
+
+// Failed returns true if the recording TB has been marked as failed
+func (r *RecordingTB) Failed() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.failed
+}
+
+// FailNow marks the recording TB as failed and stops the calling goroutine
+func (r *RecordingTB) FailNow() {
+	r.Fail()
+	runtime.Goexit()
+}
+
+// Error records a failure with a message formatted as by fmt.Sprintln (without the final newline)
+func (r *RecordingTB) Error(args ...interface{}) {
+	r.record(RecordError, sprintln(args...))
+}
+
+// Errorf records a failure with a message formatted as by fmt.Sprintf
+func (r *RecordingTB) Errorf(format string, args ...interface{}) {
+	r.record(RecordError, fmt.Sprintf(format, args...))
+}
+
+// Fatal records a failure as Error does and stops the calling goroutine
+func (r *RecordingTB) Fatal(args ...interface{}) {
+	r.record(RecordFatal, sprintln(args...))
+	runtime.Goexit()
+}
+
+// Fatalf records a failure as Errorf does and stops the calling goroutine
+func (r *RecordingTB) Fatalf(format string, args ...interface{}) {
+	r.record(RecordFatal, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+// Skip records a skip with a message formatted as by fmt.Sprintln (without the final newline) and stops the
+// calling goroutine
+func (r *RecordingTB) Skip(args ...interface{}) {
+	r.record(RecordSkip, sprintln(args...))
+	runtime.Goexit()
+}
+
+// Skipf records a skip with a message formatted as by fmt.Sprintf and stops the calling goroutine
+func (r *RecordingTB) Skipf(format string, args ...interface{}) {
+	r.record(RecordSkip, fmt.Sprintf(format, args...))
+	runtime.Goexit()
+}
+
+// SkipNow marks the recording TB as skipped and stops the calling goroutine
+func (r *RecordingTB) SkipNow() {
+	r.lock.Lock()
+	r.skipped = true
+	r.lock.Unlock()
+	runtime.Goexit()
+}
+
+// Skipped returns true if the recording TB has been skipped
+func (r *RecordingTB) Skipped() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.skipped
+}
+
+func (r *RecordingTB) record(kind RecordKind, msg string) {
+	r.lock.Lock()
+	r.records = append(r.records, Record{Kind: kind, Message: msg})
+	switch kind {
+	case RecordError, RecordFatal:
+		r.failed = true
+	case RecordSkip:
+		r.skipped = true
+	}
+	r.lock.Unlock()
+}
+
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
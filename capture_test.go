@@ -0,0 +1,74 @@
+package testutils
+
+import "testing"
+
+func TestCaptureFailure(t *testing.T) {
+	failed, msg := CaptureFailure(func(tb testing.TB) {
+		CheckEqual(1, 2, tb)
+		t.Error("Fatalf did not stop the function")
+	}, t)
+	CheckTrue(failed, t)
+	CheckEqual("Expected equal: int 1, got int 2", msg, t)
+
+	failed, msg = CaptureFailure(func(tb testing.TB) {
+		ExpectEqual(1, 2, tb)
+		tb.Error("a", 1)
+	}, t)
+	CheckTrue(failed, t)
+	CheckEqual("Expected equal: int 1, got int 2\na 1", msg, t)
+
+	failed, msg = CaptureFailure(func(tb testing.TB) {
+		CheckEqual(1, 1, tb)
+		_ = tb.TempDir()
+		tb.Skip("skipped")
+		tb.Fail()
+	}, t)
+	CheckFalse(failed, t)
+	CheckEqual("", msg, t)
+
+	failed, _ = CaptureFailure(func(tb testing.TB) { tb.FailNow() }, t)
+	CheckTrue(failed, t)
+}
+
+func TestRecordingTB(t *testing.T) {
+	r := NewRecordingTB(nil)
+	CheckEqual("RecordingTB", r.Name(), t)
+	r.Run(func(tb testing.TB) {
+		tb.Log("log", 1)
+		tb.Logf("logf %d", 2)
+		tb.Errorf("errorf %d", 3)
+		tb.Fatal("fatal", 4)
+		tb.Error("not reached")
+	})
+	CheckTrue(r.Failed(), t)
+	CheckFalse(r.Skipped(), t)
+	CheckEqual([]Record{
+		{RecordLog, "log 1"},
+		{RecordLog, "logf 2"},
+		{RecordError, "errorf 3"},
+		{RecordFatal, "fatal 4"},
+	}, r.Records(), t)
+	CheckEqual([]string{"log 1", "logf 2"}, r.Logs(), t)
+	CheckEqual([]string{"errorf 3", "fatal 4"}, r.Failures(), t)
+
+	r = NewRecordingTB(t)
+	CheckEqual(t.Name(), r.Name(), t)
+	r.Run(func(tb testing.TB) {
+		tb.Skipf("skipf %s", "x")
+	})
+	CheckFalse(r.Failed(), t)
+	CheckTrue(r.Skipped(), t)
+	CheckEqual([]Record{{RecordSkip, "skipf x"}}, r.Records(), t)
+
+	r = NewRecordingTB(t)
+	r.Run(func(tb testing.TB) { tb.SkipNow() })
+	CheckTrue(r.Skipped(), t)
+
+	r = NewRecordingTB(t)
+	r.Run(func(tb testing.TB) {
+		tb.Log("before")
+		panic("boom")
+	})
+	CheckTrue(r.Failed(), t)
+	CheckEqual([]Record{{RecordLog, "before"}, {RecordFatal, "panic: boom"}}, r.Records(), t)
+}
@@ -0,0 +1,114 @@
+package testutils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// The checks in this file take a channel of any element type (with receive direction) as an interface{}
+
+// CheckReceivesWithin checks that a value is received from the channel within the given timeout, and that it is
+// equal to the expected value as determined by CheckEqual. It is a failure if the channel is closed.
+func CheckReceivesWithin(ch interface{}, timeout time.Duration, expected interface{}, t testing.TB) {
+	t.Helper()
+	CheckReceivesWithinWith(ch, timeout, expected, ClockOptions{}, t)
+}
+
+// CheckReceivesWithinWith is CheckReceivesWithin with options
+func CheckReceivesWithinWith(ch interface{}, timeout time.Duration, expected interface{}, opts ClockOptions, t testing.TB) {
+	cv, ok := receiveChannel(ch)
+	if !ok {
+		t.Helper()
+		t.Fatalf("Expected a channel with receive direction, got %T", ch)
+		return
+	}
+	v, received, timedOut := receiveWithin(cv, timeout, opts.clock())
+	switch {
+	case timedOut:
+		t.Helper()
+		t.Fatalf("Expected to receive %v within %v, but nothing was received", expected, timeout)
+	case !received:
+		t.Helper()
+		t.Fatalf("Expected to receive %v within %v, but the channel was closed", expected, timeout)
+	case !valuesEqual(expected, v.Interface()):
+		t.Helper()
+		t.Fatalf("Expected to receive %T %v, got %T %v", expected, expected, v.Interface(), v.Interface())
+	}
+}
+
+// CheckNoReceiveWithin checks that nothing is received from the channel during the given duration, which means that
+// the check always takes d to complete unless it fails. It is a failure if the channel is closed.
+func CheckNoReceiveWithin(ch interface{}, d time.Duration, t testing.TB) {
+	t.Helper()
+	CheckNoReceiveWithinWith(ch, d, ClockOptions{}, t)
+}
+
+// CheckNoReceiveWithinWith is CheckNoReceiveWithin with options
+func CheckNoReceiveWithinWith(ch interface{}, d time.Duration, opts ClockOptions, t testing.TB) {
+	cv, ok := receiveChannel(ch)
+	if !ok {
+		t.Helper()
+		t.Fatalf("Expected a channel with receive direction, got %T", ch)
+		return
+	}
+	v, received, timedOut := receiveWithin(cv, d, opts.clock())
+	switch {
+	case timedOut:
+	case !received:
+		t.Helper()
+		t.Fatalf("Expected nothing to be received within %v, but the channel was closed", d)
+	default:
+		t.Helper()
+		t.Fatalf("Expected nothing to be received within %v, got %T %v", d, v.Interface(), v.Interface())
+	}
+}
+
+// CheckChannelClosed checks that the channel is closed, i.e. that a receive from it does not block and does not
+// produce a value. A value that is buffered in the channel is received by the check, and reported as a failure.
+func CheckChannelClosed(ch interface{}, t testing.TB) {
+	cv, ok := receiveChannel(ch)
+	if !ok {
+		t.Helper()
+		t.Fatalf("Expected a channel with receive direction, got %T", ch)
+		return
+	}
+	chosen, v, received := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: cv},
+		{Dir: reflect.SelectDefault},
+	})
+	switch {
+	case chosen == 1:
+		t.Helper()
+		t.Fatalf("Expected channel to be closed, but it is open")
+	case received:
+		t.Helper()
+		t.Fatalf("Expected channel to be closed, but received %T %v", v.Interface(), v.Interface())
+	}
+}
+
+// receiveChannel returns the reflected channel and true if ch is a channel that can be received from
+func receiveChannel(ch interface{}) (reflect.Value, bool) {
+	cv := reflect.ValueOf(ch)
+	return cv, cv.Kind() == reflect.Chan && cv.Type().ChanDir()&reflect.RecvDir != 0
+}
+
+// receiveWithin receives from the channel, and returns the received value and whether it was received (false if the
+// channel was closed), or timedOut true if nothing was received within the timeout as measured by the clock
+func receiveWithin(cv reflect.Value, timeout time.Duration, clock Clock) (v reflect.Value, received, timedOut bool) {
+	// a ready value must win over an expired timer, which a select with both cases does not guarantee
+	chosen, v, received := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: cv},
+		{Dir: reflect.SelectDefault},
+	})
+	if chosen == 0 {
+		return v, received, false
+	}
+	timer := clock.NewTimer(timeout)
+	defer timer.Stop()
+	chosen, v, received = reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: cv},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C())},
+	})
+	return v, received, chosen == 1
+}
@@ -0,0 +1,95 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckReceivesWithin(t *testing.T) {
+	ch := make(chan int, 1)
+	time.AfterFunc(time.Millisecond, func() { ch <- 3 })
+	CheckReceivesWithin(ch, time.Second, 3, t)
+
+	type event struct{ Name string }
+	events := make(chan event, 1)
+	events <- event{"a"}
+	CheckReceivesWithin((<-chan event)(events), time.Second, event{"a"}, t)
+
+	m := &messageTB{}
+	CheckReceivesWithin(ch, time.Millisecond, 3, m)
+	ch <- 4
+	CheckReceivesWithin(ch, time.Millisecond, 3, m)
+	close(ch)
+	CheckReceivesWithin(ch, time.Millisecond, 3, m)
+	CheckReceivesWithin(make(chan<- int), time.Millisecond, 3, m)
+	CheckEqual([]string{
+		"Expected to receive 3 within 1ms, but nothing was received",
+		"Expected to receive int 3, got int 4",
+		"Expected to receive 3 within 1ms, but the channel was closed",
+		"Expected a channel with receive direction, got chan<- int",
+	}, m.messages, t)
+}
+
+func TestCheckNoReceiveWithin(t *testing.T) {
+	ch := make(chan string, 1)
+	CheckNoReceiveWithin(ch, time.Millisecond, t)
+
+	m := &messageTB{}
+	ch <- "x"
+	CheckNoReceiveWithin(ch, time.Millisecond, m)
+	close(ch)
+	CheckNoReceiveWithin(ch, time.Millisecond, m)
+	CheckNoReceiveWithin("ch", time.Millisecond, m)
+	CheckEqual([]string{
+		"Expected nothing to be received within 1ms, got string x",
+		"Expected nothing to be received within 1ms, but the channel was closed",
+		"Expected a channel with receive direction, got string",
+	}, m.messages, t)
+}
+
+func TestCheckReceivesWithinWith(t *testing.T) {
+	clock := NewFakeClock(clockStart)
+	opts := ClockOptions{Clock: clock}
+	ch := make(chan int)
+	go func() {
+		clock.Sleep(time.Minute)
+		ch <- 3
+	}()
+	advanceWhenWaiting(clock, 2, time.Minute)
+	CheckReceivesWithinWith(ch, time.Hour, 3, opts, t)
+
+	m := &messageTB{}
+	advanceWhenWaiting(clock, 1, time.Hour)
+	CheckReceivesWithinWith(ch, time.Hour, 3, opts, m)
+	CheckEqual([]string{"Expected to receive 3 within 1h0m0s, but nothing was received"}, m.messages, t)
+}
+
+func TestCheckNoReceiveWithinWith(t *testing.T) {
+	clock := NewFakeClock(clockStart)
+	ch := make(chan string, 1)
+	advanceWhenWaiting(clock, 1, time.Hour)
+	CheckNoReceiveWithinWith(ch, time.Hour, ClockOptions{Clock: clock}, t)
+	CheckEqual(clockStart.Add(time.Hour), clock.Now(), t)
+}
+
+func TestCheckChannelClosed(t *testing.T) {
+	ch := make(chan struct{}, 1)
+	m := &messageTB{}
+	CheckChannelClosed(ch, m)
+	ch <- struct{}{}
+	CheckChannelClosed(ch, m)
+	close(ch)
+	CheckChannelClosed(ch, t)
+	CheckEqual([]string{
+		"Expected channel to be closed, but it is open",
+		"Expected channel to be closed, but received struct {} {}",
+	}, m.messages, t)
+}
+
+func TestCheckReceivesWithin_zeroTimeout(t *testing.T) {
+	ch := make(chan int, 1)
+	for i := 0; i < 100; i++ {
+		ch <- i
+		CheckReceivesWithin(ch, 0, i, t)
+	}
+}
@@ -5,26 +5,25 @@
 package testutils
 
 import (
-	"bytes"
-	"io"
 	"math"
 	"os"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 )
 
-func unequalValues(e, g interface{}, t *testing.T) {
+func unequalValues(e, g interface{}, t testing.TB) {
 	t.Helper()
 	t.Fatalf("Expected equal: %T %v, got %T %v", e, e, g, g)
 }
-func equalValues(e, g interface{}, t *testing.T) {
+func equalValues(e, g interface{}, t testing.TB) {
 	t.Helper()
 	t.Fatalf("Expected not equal: %T %v, got %T %v", e, e, g, g)
 }
 
 // CheckEqual checks if two values are deeply equal and calls t.Fatalf if not
-func CheckEqual(expected interface{}, got interface{}, t *testing.T) {
+func CheckEqual(expected interface{}, got interface{}, t testing.TB) {
 	if !valuesEqual(expected, got) {
 		t.Helper()
 		unequalValues(expected, got, t)
@@ -32,7 +31,7 @@ func CheckEqual(expected interface{}, got interface{}, t *testing.T) {
 }
 
 // CheckNotEqual checks if two values are deeply equal and calls t.Fatalf if not
-func CheckNotEqual(expected interface{}, got interface{}, t *testing.T) {
+func CheckNotEqual(expected interface{}, got interface{}, t testing.TB) {
 	if valuesEqual(expected, got) {
 		t.Helper()
 		equalValues(expected, got, t)
@@ -42,7 +41,7 @@ func CheckNotEqual(expected interface{}, got interface{}, t *testing.T) {
 // CheckMatches checks expected regular expression is matched by the given string and calls t.Fatalf if not
 //
 // The expected regular expression can be either a *regexp.Regexp or a string that represents a valid regexp
-func CheckMatches(expected interface{}, got string, t *testing.T) {
+func CheckMatches(expected interface{}, got string, t testing.TB) {
 	var rx *regexp.Regexp
 	switch expected := expected.(type) {
 	case *regexp.Regexp:
@@ -52,9 +51,11 @@ func CheckMatches(expected interface{}, got string, t *testing.T) {
 		rx, err = regexp.Compile(expected)
 		if err != nil {
 			t.Fatalf("CheckMatches: illegal regexp %q", expected)
+			return
 		}
 	default:
 		t.Fatalf("CheckMatches: first argument must be a regexp or a string, got %T %v", expected, expected)
+		return
 	}
 	if !rx.MatchString(got) {
 		t.Helper()
@@ -83,8 +84,39 @@ func valuesEqual(a interface{}, b interface{}) bool {
 	return nc == 0 || nc == -2 && ok
 }
 
+// CheckReflectEqual checks if the values held by two reflect.Value are equal and calls t.Fatalf if not. Numeric
+// values are compared the same way as in CheckEqual, i.e. regardless of type and bit size. Other values must
+// be of the same type and are compared field by field, element by element, in which case the path to the first
+// difference is reported on failure. Values held in interfaces are compared as the values they hold.
+func CheckReflectEqual(expected, got reflect.Value, t testing.TB) {
+	for expected.Kind() == reflect.Interface && !expected.IsNil() {
+		expected = expected.Elem()
+	}
+	for got.Kind() == reflect.Interface && !got.IsNil() {
+		got = got.Elem()
+	}
+	if expected.IsValid() && got.IsValid() && expected.CanInterface() && got.CanInterface() {
+		if nc := numericCompare(expected.Interface(), got.Interface()); nc != -2 {
+			if nc != 0 {
+				t.Helper()
+				unequalValues(expected.Interface(), got.Interface(), t)
+			}
+			return
+		}
+	}
+	if expected.IsValid() && got.IsValid() && expected.Type() != got.Type() {
+		t.Helper()
+		t.Fatalf("Expected equal: value of type %s, got value of type %s", expected.Type(), got.Type())
+		return
+	}
+	if path, ok := firstDifference(expected, got, "value"); ok {
+		t.Helper()
+		t.Fatalf("Expected equal: %v, got %v (%s differs)", expected, got, path)
+	}
+}
+
 // CheckEqualAndNoError checks there is no error, and that two values are deeply equal and calls t.Fatalf if not
-func CheckEqualAndNoError(expected interface{}, got interface{}, gotError error, t *testing.T) {
+func CheckEqualAndNoError(expected interface{}, got interface{}, gotError error, t testing.TB) {
 	t.Helper()
 	CheckNotError(gotError, t)
 	if !reflect.DeepEqual(expected, got) {
@@ -93,7 +125,7 @@ func CheckEqualAndNoError(expected interface{}, got interface{}, gotError error,
 }
 
 // CheckContainsElements checks if one slice contains all elements of another slice irrespective of order and uniqueness.
-func CheckContainsElements(expected interface{}, got interface{}, t *testing.T) {
+func CheckContainsElements(expected interface{}, got interface{}, t testing.TB) {
 	if sliceContains(got, expected, false) {
 		t.Helper()
 		t.Fatalf("Slice %v does not contain all elements in %v", got, expected)
@@ -101,7 +133,7 @@ func CheckContainsElements(expected interface{}, got interface{}, t *testing.T)
 }
 
 // CheckEqualElements checks if two slices contains the exact same set of elements irrespective of order and uniqueness.
-func CheckEqualElements(expected interface{}, got interface{}, t *testing.T) {
+func CheckEqualElements(expected interface{}, got interface{}, t testing.TB) {
 	if !sliceContains(got, expected, true) {
 		t.Helper()
 		t.Fatalf("Elements of slice %v and %v differ", expected, got)
@@ -156,8 +188,37 @@ nextB:
 	return true
 }
 
+// CheckTextEqual checks if two strings are equal and calls t.Fatalf with a diff of the two if not.
+// See Tester.CheckTextEqual.
+func CheckTextEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	NewTester(t).CheckTextEqual(expected, got)
+}
+
+// CheckStringSlicesEqual checks if two string slices are equal and calls t.Fatalf with a line by line diff of
+// the two if not. See Tester.CheckStringSlicesEqual.
+func CheckStringSlicesEqual(expected, got []string, t testing.TB) {
+	t.Helper()
+	NewTester(t).CheckStringSlicesEqual(expected, got)
+}
+
+// CheckTimeWithin checks if the absolute difference between the got and the expected time is at most delta, which
+// is a convenient way to check that a time is "roughly now". See Tester.CheckTimeWithin.
+func CheckTimeWithin(expected, got time.Time, delta time.Duration, t testing.TB) {
+	t.Helper()
+	NewTester(t).CheckTimeWithin(expected, got, delta)
+}
+
+// CheckTimesEqual checks if the got and the expected time are the same instant in the same location, ignoring
+// the monotonic clock reading which makes reflect.DeepEqual (and ==) fail for equal times. Use a tester created
+// with the IgnoreTimeZone option to compare the times in UTC. See Tester.CheckTimesEqual.
+func CheckTimesEqual(expected, got time.Time, t testing.TB) {
+	t.Helper()
+	NewTester(t).CheckTimesEqual(expected, got)
+}
+
 // CheckNil checks if value is nil
-func CheckNil(got interface{}, t *testing.T) {
+func CheckNil(got interface{}, t testing.TB) {
 	rf := reflect.ValueOf(got)
 	if rf.IsValid() && !rf.IsNil() {
 		t.Helper()
@@ -166,7 +227,7 @@ func CheckNil(got interface{}, t *testing.T) {
 }
 
 // CheckNotNil checks if value is not nil
-func CheckNotNil(got interface{}, t *testing.T) {
+func CheckNotNil(got interface{}, t testing.TB) {
 	rf := reflect.ValueOf(got)
 	if !rf.IsValid() || rf.IsNil() {
 		t.Helper()
@@ -175,7 +236,7 @@ func CheckNotNil(got interface{}, t *testing.T) {
 }
 
 // CheckError checks if there is an error
-func CheckError(got interface{}, t *testing.T) {
+func CheckError(got interface{}, t testing.TB) {
 	_, ok := got.(error)
 	if !ok {
 		t.Helper()
@@ -184,7 +245,7 @@ func CheckError(got interface{}, t *testing.T) {
 }
 
 // CheckNotError checks if value is not an error
-func CheckNotError(got interface{}, t *testing.T) {
+func CheckNotError(got interface{}, t testing.TB) {
 	err, ok := got.(error)
 	if ok {
 		t.Helper()
@@ -194,7 +255,7 @@ func CheckNotError(got interface{}, t *testing.T) {
 
 // CheckNumericGreater checks if second value is greater than first. Comparisons are made regardless of
 // bit size and an integer is equal to a float if casting it to a float makes it equal.
-func CheckNumericGreater(expected interface{}, got interface{}, t *testing.T) {
+func CheckNumericGreater(expected interface{}, got interface{}, t testing.TB) {
 	if numericCompare(expected, got) != 1 {
 		t.Helper()
 		t.Fatalf("Expected: %T %v greater than %T %v", expected, expected, got, got)
@@ -203,7 +264,7 @@ func CheckNumericGreater(expected interface{}, got interface{}, t *testing.T) {
 
 // CheckNumericLess checks if second value is less than first. Comparisons are made regardless of
 // bit size and an integer is equal to a float if casting it to a float makes it equal.
-func CheckNumericLess(expected interface{}, got interface{}, t *testing.T) {
+func CheckNumericLess(expected interface{}, got interface{}, t testing.TB) {
 	if numericCompare(expected, got) != -1 {
 		t.Helper()
 		t.Fatalf("Expected: %T %v less than %T %v", expected, expected, got, got)
@@ -339,7 +400,7 @@ func AsInterface(v interface{}) (rv interface{}, ok bool) {
 }
 
 // CheckTrue checks if value is true
-func CheckTrue(got bool, t *testing.T) {
+func CheckTrue(got bool, t testing.TB) {
 	if !got {
 		t.Helper()
 		t.Fatalf("Expected: true, got %v", got)
@@ -347,7 +408,7 @@ func CheckTrue(got bool, t *testing.T) {
 }
 
 // CheckFalse checks if value is false
-func CheckFalse(got bool, t *testing.T) {
+func CheckFalse(got bool, t testing.TB) {
 	if got {
 		t.Helper()
 		t.Fatalf("Expected: false, got %v", got)
@@ -356,77 +417,144 @@ func CheckFalse(got bool, t *testing.T) {
 
 const chunkSize = 0x10000
 
-// CheckFilesEqual equals checks if the two files have the exact same contents.
-func CheckFilesEqual(file1, file2 string, t *testing.T) {
+// maxTextDiffSize is the max size of files shown as a text diff by CheckFilesEqual
+const maxTextDiffSize = 1 << 20
+
+// CheckFilesEqual equals checks if the two files have the exact same contents. On failure, the first differing
+// region is shown as a unified diff if both files are text, and otherwise as a hex dump of both files at the
+// offset of the first differing byte.
+func CheckFilesEqual(file1, file2 string, t testing.TB) {
 	t.Helper()
 	var fi1, fi2 os.FileInfo
 	var err error
 	if fi1, err = os.Stat(file1); err != nil {
 		t.Fatal(err)
+		return
 	}
 
 	if fi2, err = os.Stat(file2); err != nil {
 		t.Fatal(err)
+		return
 	}
 
 	if fi1.IsDir() {
 		t.Fatalf("%q is a directory", file1)
+		return
 	}
 
 	if fi2.IsDir() {
 		t.Fatalf("%q is a directory", file2)
-	}
-
-	sz := fi1.Size()
-	if sz != fi2.Size() {
-		t.Fatalf("size of file %q (%d), does not match size of %q (%d)", file1, fi1.Size(), file2, fi2.Size())
+		return
 	}
 
 	var f1, f2 *os.File
 	if f1, err = os.Open(file1); err != nil {
 		t.Fatal(err)
+		return
 	}
 	defer f1.Close()
 
-	if f2, err = os.Open(file1); err != nil {
+	if f2, err = os.Open(file2); err != nil {
 		t.Fatal(err)
+		return
 	}
 	defer f2.Close()
 
-	if sz > chunkSize {
-		sz = chunkSize
+	offset, equal, err := firstDiffOffset(f1, f2)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if equal {
+		return
 	}
-	b1 := make([]byte, sz)
-	b2 := make([]byte, sz)
-	for {
-		n1, err1 := f1.Read(b1)
-		n2, err2 := f2.Read(b2)
-
-		if err1 == io.EOF && err2 == io.EOF {
-			return
-		}
-
-		if err1 != nil {
-			t.Fatal(err1)
-		}
 
-		if err2 != nil {
-			t.Fatal(err2)
+	var diff string
+	if fi1.Size() <= maxTextDiffSize && fi2.Size() <= maxTextDiffSize {
+		var c1, c2 []byte
+		if c1, err = os.ReadFile(file1); err == nil {
+			c2, err = os.ReadFile(file2)
 		}
-
-		if !bytes.Equal(b1[0:n1], b2[0:n2]) {
-			t.Fatalf("content of file %q and %q differ", file1, file2)
+		if err != nil {
+			t.Fatal(err)
+			return
 		}
+		diff = firstHunk(contentDiff(c1, c2))
+	} else {
+		diff = hexDiff(readWindow(f1, offset), readWindow(f2, offset), offset)
 	}
+	t.Fatalf("content of file %q (%d bytes) and %q (%d bytes) differ:\n%s", file1, fi1.Size(), file2, fi2.Size(), diff)
 }
 
 // CheckFileExists checks that given file name is for an existing regular file
-func CheckFileExists(filename string, t *testing.T) {
+func CheckFileExists(filename string, t testing.TB) {
+	t.Helper()
 	info, err := os.Stat(filename)
 	if os.IsNotExist(err) {
 		t.Fatalf("file %s does not exist", filename)
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+		return
 	}
 	if info.IsDir() {
 		t.Fatalf("file %s is a directory, not a file", filename)
 	}
 }
+
+// CheckFileNotExists checks that there is no file or directory with the given name
+func CheckFileNotExists(filename string, t testing.TB) {
+	t.Helper()
+	info, err := os.Lstat(filename)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if info.IsDir() {
+		t.Fatalf("directory %s exists", filename)
+		return
+	}
+	t.Fatalf("file %s exists", filename)
+}
+
+// CheckDirExists checks that given name is for an existing directory
+func CheckDirExists(dirname string, t testing.TB) {
+	t.Helper()
+	info, err := os.Stat(dirname)
+	if os.IsNotExist(err) {
+		t.Fatalf("directory %s does not exist", dirname)
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if !info.IsDir() {
+		t.Fatalf("directory %s is a file, not a directory", dirname)
+	}
+}
+
+// CheckDirEmpty checks that given name is for an existing directory that is empty
+func CheckDirEmpty(dirname string, t testing.TB) {
+	t.Helper()
+	entries, err := os.ReadDir(dirname)
+	if os.IsNotExist(err) {
+		t.Fatalf("directory %s does not exist", dirname)
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if len(entries) > 0 {
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.Name()
+		}
+		t.Fatalf("directory %s is not empty, it contains: %v", dirname, names)
+	}
+}
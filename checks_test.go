@@ -2,7 +2,11 @@ package testutils
 
 import (
 	"io"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func ensureFailed(t *testing.T, f func(t *testing.T)) {
@@ -96,6 +100,24 @@ func TestCheckEqualElements(t *testing.T) {
 	})
 }
 
+func TestCheckTextEqual(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckTextEqual("a\nb", "a\nb", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTextEqual("a\nb", "a\nc", ft)
+	})
+}
+
+func TestCheckStringSlicesEqual(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckStringSlicesEqual([]string{"a", "b"}, []string{"a", "b"}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckStringSlicesEqual([]string{"a", "b"}, []string{"a"}, ft)
+	})
+}
+
 func Test_valuesEqual(t *testing.T) {
 	if !valuesEqual(1, 1) {
 		t.Fail()
@@ -119,3 +141,123 @@ func Test_valuesEqual(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestCheckReflectEqual(t *testing.T) {
+	type point struct {
+		X, Y int
+		tags []string
+	}
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckReflectEqual(reflect.ValueOf(int8(1)), reflect.ValueOf(1.0), ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckReflectEqual(reflect.ValueOf(point{1, 2, []string{"a"}}), reflect.ValueOf(point{1, 2, []string{"a"}}), ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckReflectEqual(reflect.ValueOf([]interface{}{1}).Index(0), reflect.ValueOf(1), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReflectEqual(reflect.ValueOf(point{1, 2, []string{"a"}}), reflect.ValueOf(point{1, 2, []string{"b"}}), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReflectEqual(reflect.ValueOf(2), reflect.ValueOf(1.0), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReflectEqual(reflect.ValueOf("1"), reflect.ValueOf(1), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReflectEqual(reflect.Value{}, reflect.ValueOf(1), ft)
+	})
+}
+
+func TestCheckFileExists(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "a")
+	if err := os.WriteFile(name, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	CheckFileExists(name, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileExists(dir, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileExists(filepath.Join(dir, "missing"), ft)
+	})
+}
+
+func TestCheckFileNotExists(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "a")
+	CheckFileNotExists(name, t)
+	if err := os.WriteFile(name, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileNotExists(name, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileNotExists(dir, ft)
+	})
+}
+
+func TestCheckDirExists(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "a")
+	if err := os.WriteFile(name, []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	CheckDirExists(dir, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDirExists(name, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDirExists(filepath.Join(dir, "missing"), ft)
+	})
+}
+
+func TestCheckDirEmpty(t *testing.T) {
+	dir := t.TempDir()
+	CheckDirEmpty(dir, t)
+	if err := os.WriteFile(filepath.Join(dir, "a"), []byte("a"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDirEmpty(dir, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDirEmpty(filepath.Join(dir, "missing"), ft)
+	})
+}
+
+func TestCheckTimeWithin(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	CheckTimeWithin(now, now.Add(time.Second), time.Second, t)
+	CheckTimeWithin(now, now.Add(-time.Second), time.Second, t)
+	CheckTimeWithin(now, now.In(time.FixedZone("CET", 3600)), 0, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTimeWithin(now, now.Add(-2*time.Second), time.Second, ft)
+	})
+
+	r := NewRecordingTB(t)
+	r.Run(func(tb testing.TB) { ExpectTimeWithin(now, now.Add(1500*time.Millisecond), time.Second, tb) })
+	CheckEqual([]string{"Expected: time within 1s of 2024-01-01 12:00:00 +0000 UTC, got 2024-01-01 12:00:01.5 +0000 UTC (diff 1.5s)"}, r.Failures(), t)
+}
+
+func TestCheckTimesEqual(t *testing.T) {
+	now := time.Now()
+	CheckTimesEqual(now, now.Round(0), t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTimesEqual(now, now.Add(time.Nanosecond), ft)
+	})
+
+	utc := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cet := utc.In(time.FixedZone("CET", 3600))
+	NewTesterWith(t, IgnoreTimeZone()).CheckTimesEqual(utc, cet)
+	r := NewRecordingTB(t)
+	r.Run(func(tb testing.TB) { ExpectTimesEqual(utc, cet, tb) })
+	CheckEqual([]string{"Expected: time 2024-01-01 12:00:00 +0000 UTC in location UTC, got 2024-01-01 13:00:00 +0100 CET in location CET"}, r.Failures(), t)
+
+	r = NewRecordingTB(t)
+	r.Run(func(tb testing.TB) { NewTesterWith(tb, IgnoreTimeZone()).CheckTimesEqual(utc, cet.Add(time.Second)) })
+	CheckEqual([]string{"Expected: time 2024-01-01 12:00:00 +0000 UTC, got 2024-01-01 12:00:01 +0000 UTC (diff 1s)"}, r.Failures(), t)
+}
@@ -0,0 +1,188 @@
+package testutils
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by code that should be testable without depending on the real clock. Code
+// under test uses SystemClock in production and a FakeClock in tests.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// After waits for the duration to pass and then sends the current time on the returned channel
+	After(d time.Duration) <-chan time.Time
+	// NewTimer returns a new Timer that sends the current time on its channel after at least duration d
+	NewTimer(d time.Duration) Timer
+	// Sleep pauses the calling goroutine for at least the duration d
+	Sleep(d time.Duration)
+}
+
+// Timer is a timer created by a Clock. It works like time.Timer.
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, and returns false if it has already fired or been stopped
+	Stop() bool
+	// Reset changes the timer to fire after duration d, and returns true if it had been active
+	Reset(d time.Duration) bool
+}
+
+// SystemClock is the Clock that uses the real time of the system
+var SystemClock Clock = systemClock{}
+
+// ClockOptions are the options of CheckRateLimitedWith, CheckDeadlineRespectedWith, CheckReceivesWithinWith,
+// CheckNoReceiveWithinWith, and CheckCompletesWithinWith
+type ClockOptions struct {
+	// Clock is the clock used to measure and wait for time, SystemClock if not set. With a FakeClock, a timeout of
+	// the check only expires when the clock is advanced, either by the code under test or by the test from another
+	// goroutine (FakeClock.Waiters tells when the check is waiting).
+	Clock Clock
+}
+
+// clock returns the Clock of the options, or SystemClock if not set
+func (o ClockOptions) clock() Clock {
+	if o.Clock == nil {
+		return SystemClock
+	}
+	return o.Clock
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTimer(d time.Duration) Timer         { return systemTimer{time.NewTimer(d)} }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (st systemTimer) C() <-chan time.Time        { return st.t.C }
+func (st systemTimer) Stop() bool                 { return st.t.Stop() }
+func (st systemTimer) Reset(d time.Duration) bool { return st.t.Reset(d) }
+
+// FakeClock is a Clock where time only moves when the test calls Advance. Timers, After, and Sleep fire when the
+// clock is advanced to or past their deadline, which makes time dependent code deterministic and fast to test.
+// Note that Sleep blocks until another goroutine advances the clock. A FakeClock is safe for use by multiple
+// goroutines.
+type FakeClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFakeClock returns a FakeClock that starts at the given time
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the current time of the fake clock
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d and fires all timers with a deadline at or before the new time, in deadline
+// order
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	var due, pending []*fakeTimer
+	for _, ft := range c.timers {
+		if !ft.at.After(now) {
+			due = append(due, ft)
+		} else {
+			pending = append(pending, ft)
+		}
+	}
+	c.timers = pending
+	c.lock.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, ft := range due {
+		ft.fire(now)
+	}
+}
+
+// Waiters returns the number of active timers (including those of After and Sleep). This is useful to wait until
+// the code under test is waiting for the clock before advancing it.
+func (c *FakeClock) Waiters() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.timers)
+}
+
+// After returns a channel that receives the time of the clock when it has been advanced by at least d
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer returns a Timer that fires when the clock has been advanced by at least d. A timer with a duration of
+// zero or less fires immediately.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	ft := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+	ft.Reset(d)
+	return ft
+}
+
+// Sleep blocks until the clock has been advanced by at least d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// remove removes the timer from the active timers and returns true if it was active. Must be called with the lock
+// held.
+func (c *FakeClock) remove(ft *fakeTimer) bool {
+	for i, x := range c.timers {
+		if x == ft {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func (ft *fakeTimer) C() <-chan time.Time {
+	return ft.c
+}
+
+func (ft *fakeTimer) Stop() bool {
+	ft.clock.lock.Lock()
+	defer ft.clock.lock.Unlock()
+	return ft.clock.remove(ft)
+}
+
+func (ft *fakeTimer) Reset(d time.Duration) bool {
+	c := ft.clock
+	c.lock.Lock()
+	active := c.remove(ft)
+	ft.at = c.now.Add(d)
+	now := c.now
+	if d > 0 {
+		c.timers = append(c.timers, ft)
+	}
+	c.lock.Unlock()
+	if d <= 0 {
+		ft.fire(now)
+	}
+	return active
+}
+
+// fire sends the time on the channel of the timer unless a value is already waiting, as time.Timer does
+func (ft *fakeTimer) fire(now time.Time) {
+	select {
+	case ft.c <- now:
+	default:
+	}
+}
@@ -0,0 +1,71 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+var clockStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClock_Timers(t *testing.T) {
+	clock := NewFakeClock(clockStart)
+	CheckEqual(clockStart, clock.Now(), t)
+
+	t1 := clock.NewTimer(time.Second)
+	t2 := clock.NewTimer(2 * time.Second)
+	after := clock.After(3 * time.Second)
+	CheckEqual(3, clock.Waiters(), t)
+
+	clock.Advance(time.Second)
+	CheckReceivesWithin(t1.C(), time.Second, clockStart.Add(time.Second), t)
+	CheckNoReceiveWithin(t2.C(), time.Millisecond, t)
+	CheckFalse(t1.Stop(), t)
+
+	CheckTrue(t2.Stop(), t)
+	clock.Advance(5 * time.Second)
+	CheckNoReceiveWithin(t2.C(), time.Millisecond, t)
+	CheckReceivesWithin(after, time.Second, clockStart.Add(6*time.Second), t)
+	CheckEqual(0, clock.Waiters(), t)
+
+	CheckFalse(t2.Reset(time.Second), t)
+	CheckTrue(t2.Reset(2*time.Second), t)
+	clock.Advance(time.Second)
+	CheckNoReceiveWithin(t2.C(), time.Millisecond, t)
+	clock.Advance(time.Second)
+	CheckReceivesWithin(t2.C(), time.Second, clockStart.Add(8*time.Second), t)
+
+	CheckReceivesWithin(clock.After(0), time.Second, clockStart.Add(8*time.Second), t)
+}
+
+func TestFakeClock_Sleep(t *testing.T) {
+	clock := NewFakeClock(clockStart)
+	done := make(chan time.Time)
+	go func() {
+		clock.Sleep(time.Minute)
+		done <- clock.Now()
+	}()
+	CheckEventually(func() bool { return clock.Waiters() == 1 }, time.Second, time.Millisecond, t)
+	CheckNoReceiveWithin(done, time.Millisecond, t)
+	clock.Advance(time.Minute)
+	CheckReceivesWithin(done, time.Second, clockStart.Add(time.Minute), t)
+}
+
+func TestSystemClock(t *testing.T) {
+	before := time.Now()
+	SystemClock.Sleep(time.Millisecond)
+	CheckFalse(SystemClock.Now().Before(before.Add(time.Millisecond)), t)
+	timer := SystemClock.NewTimer(time.Hour)
+	CheckTrue(timer.Stop(), t)
+	<-SystemClock.NewTimer(0).C()
+	<-SystemClock.After(time.Millisecond)
+}
+
+// advanceWhenWaiting advances the clock by d from a new goroutine, once n timers are waiting for the clock
+func advanceWhenWaiting(clock *FakeClock, n int, d time.Duration) {
+	go func() {
+		for clock.Waiters() < n {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Advance(d)
+	}()
+}
@@ -0,0 +1,72 @@
+package testutils
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// ConcurrencyCounter counts the number of concurrently executing workers and records the peak. It is given to the
+// workload of CheckConcurrencyLimitRespected.
+type ConcurrencyCounter struct {
+	lock    sync.Mutex
+	start   time.Time
+	current int
+	peak    int
+	entries int
+	peakAt  time.Duration
+	peakNth int
+}
+
+// Enter records that a worker started executing and returns a function that records that it stopped. It is
+// typically used as `defer c.Enter()()` at the start of a worker function.
+func (c *ConcurrencyCounter) Enter() func() {
+	c.lock.Lock()
+	c.current++
+	c.entries++
+	if c.current > c.peak {
+		c.peak = c.current
+		c.peakAt = time.Since(c.start)
+		c.peakNth = c.entries
+	}
+	c.lock.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.lock.Lock()
+			c.current--
+			c.lock.Unlock()
+		})
+	}
+}
+
+// Wrap returns a function that calls the given worker function between Enter and its exit function
+func (c *ConcurrencyCounter) Wrap(worker func()) func() {
+	return func() {
+		defer c.Enter()()
+		worker()
+	}
+}
+
+// Peak returns the maximum number of workers that have executed concurrently
+func (c *ConcurrencyCounter) Peak() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.peak
+}
+
+// CheckConcurrencyLimitRespected calls the workload function with a ConcurrencyCounter and checks that no more
+// than limit workers executed concurrently while it ran. The workload should drive the implementation under test
+// (i.e. a pool or a semaphore) with workers wrapped by the counter (or that call its Enter method), and must not
+// return until all workers have finished. On failure the peak and when it was first reached are reported.
+func CheckConcurrencyLimitRespected(limit int, workload func(c *ConcurrencyCounter), t testing.TB) {
+	c := &ConcurrencyCounter{start: time.Now()}
+	workload(c)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.peak > limit {
+		t.Helper()
+		t.Fatalf("Expected at most %d concurrent executions, got a peak of %d reached by execution %d of %d after %v",
+			limit, c.peak, c.peakNth, c.entries, c.peakAt)
+	}
+}
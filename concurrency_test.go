@@ -0,0 +1,51 @@
+package testutils
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// runPool runs n jobs with at most size jobs executing concurrently
+func runPool(size, n int, job func()) {
+	sem := make(chan struct{}, size)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			job()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCheckConcurrencyLimitRespected(t *testing.T) {
+	sleep := func() { time.Sleep(2 * time.Millisecond) }
+	CheckConcurrencyLimitRespected(3, func(c *ConcurrencyCounter) {
+		runPool(3, 20, c.Wrap(sleep))
+		CheckTrue(c.Peak() > 0, t)
+	}, t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckConcurrencyLimitRespected(2, func(c *ConcurrencyCounter) {
+			runPool(4, 20, c.Wrap(sleep))
+		}, ft)
+	})
+}
+
+func TestConcurrencyCounter_Enter(t *testing.T) {
+	c := &ConcurrencyCounter{start: time.Now()}
+	exit1 := c.Enter()
+	exit2 := c.Enter()
+	exit2()
+	exit2()
+	exit3 := c.Enter()
+	CheckEqual(2, c.Peak(), t)
+	exit1()
+	exit3()
+	CheckEqual(0, c.current, t)
+	CheckEqual(2, c.peakNth, t)
+}
@@ -0,0 +1,171 @@
+package testutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ContextRecorder records the contexts received by downstream calls. It is given to the entry point function of
+// CheckContextPropagated, which should arrange for Record to be called by the downstream calls, typically by
+// installing it in a fake or a hook of a dependency.
+type ContextRecorder struct {
+	lock     sync.Mutex
+	contexts []recordedContext
+}
+
+type recordedContext struct {
+	label string
+	ctx   context.Context
+}
+
+// Record records a context received by a downstream call. The label identifies the call in failure messages. It
+// is safe to call Record from multiple goroutines.
+func (r *ContextRecorder) Record(label string, ctx context.Context) {
+	r.lock.Lock()
+	r.contexts = append(r.contexts, recordedContext{label: label, ctx: ctx})
+	r.lock.Unlock()
+}
+
+// contextMarker is the key of the value that marks the context given to the entry point
+type contextMarker struct{}
+
+// CheckContextPropagated calls the entry point function with a marked context that has a value and a deadline, and
+// a ContextRecorder. It then checks that at least one downstream call was recorded, and that each recorded context
+// was derived from the marked context, i.e. that it has the marker value and a deadline that is no later than the
+// deadline of the marked context. This catches code that uses context.Background() or context.TODO() instead of
+// passing on the context it was given. On failure the labels of all offending calls are reported.
+func CheckContextPropagated(entry func(ctx context.Context, r *ContextRecorder), t testing.TB) {
+	marker := new(int)
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), contextMarker{}, marker), time.Hour)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	r := &ContextRecorder{}
+	entry(ctx, r)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.contexts) == 0 {
+		t.Helper()
+		t.Fatalf("Expected downstream calls to record their context, got no calls")
+		return
+	}
+	var bad []string
+	for i, rc := range r.contexts {
+		label := rc.label
+		if label == "" {
+			label = fmt.Sprintf("#%d", i)
+		}
+		switch {
+		case rc.ctx == nil:
+			bad = append(bad, label+" (nil context)")
+		case rc.ctx.Value(contextMarker{}) != marker:
+			bad = append(bad, label+" (not derived from the given context)")
+		default:
+			if d, ok := rc.ctx.Deadline(); !ok || d.After(deadline) {
+				bad = append(bad, label+" (deadline not preserved)")
+			}
+		}
+	}
+	if len(bad) > 0 {
+		t.Helper()
+		t.Fatalf("Expected the context to be propagated to all %d downstream calls, but not to: %s", len(r.contexts), strings.Join(bad, ", "))
+	}
+}
+
+// deadlineTimeout is the timeout of the soon expiring context used by CheckDeadlineRespected
+const deadlineTimeout = 20 * time.Millisecond
+
+// CheckDeadlineRespected checks that the function returns promptly with a context error when its context expires.
+// The function is called twice: with a context that has already expired, and with a context that expires shortly
+// after the call. Each call must return within the given grace period after the expiry of the context, with an
+// error that is (or wraps) context.DeadlineExceeded or context.Canceled. A call that does not return in time is
+// abandoned (its goroutine is left running) and reported.
+func CheckDeadlineRespected(fn func(ctx context.Context) error, grace time.Duration, t testing.TB) {
+	t.Helper()
+	CheckDeadlineRespectedWith(fn, grace, ClockOptions{}, t)
+}
+
+// CheckDeadlineRespectedWith is CheckDeadlineRespected with options. The deadlines of the contexts given to the
+// function, and the grace periods, are measured by the clock of the options.
+func CheckDeadlineRespectedWith(fn func(ctx context.Context) error, grace time.Duration, opts ClockOptions, t testing.TB) {
+	clock := opts.clock()
+	expired, cancel := withClockTimeout(clock, -time.Second)
+	defer cancel()
+	if msg := checkDeadline(expired, fn, grace, clock); msg != "" {
+		t.Helper()
+		t.Fatalf("Expected a call with an expired context %s", msg)
+		return
+	}
+	soon, cancel2 := withClockTimeout(clock, deadlineTimeout)
+	defer cancel2()
+	if msg := checkDeadline(soon, fn, deadlineTimeout+grace, clock); msg != "" {
+		t.Helper()
+		t.Fatalf("Expected a call with a context expiring after %v %s", deadlineTimeout, msg)
+	}
+}
+
+// clockContext is a context with a deadline measured by a Clock
+type clockContext struct {
+	context.Context
+	deadline time.Time
+}
+
+func (cc clockContext) Deadline() (time.Time, bool) {
+	return cc.deadline, true
+}
+
+func (cc clockContext) Err() error {
+	if cc.Context.Err() == nil {
+		return nil
+	}
+	return context.Cause(cc.Context)
+}
+
+// withClockTimeout returns a context that expires when the clock has been advanced by d, and its cancel function.
+// The context is done immediately if d is zero or less.
+func withClockTimeout(clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if clock == SystemClock {
+		return context.WithTimeout(context.Background(), d)
+	}
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cc := clockContext{Context: ctx, deadline: clock.Now().Add(d)}
+	if d <= 0 {
+		cancel(context.DeadlineExceeded)
+		return cc, func() {}
+	}
+	timer := clock.NewTimer(d)
+	go func() {
+		select {
+		case <-timer.C():
+			cancel(context.DeadlineExceeded)
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+	return cc, func() { cancel(context.Canceled) }
+}
+
+// checkDeadline calls fn with the context and returns a description of the problem if it did not return a context
+// error within the given time as measured by the clock
+func checkDeadline(ctx context.Context, fn func(ctx context.Context) error, within time.Duration, clock Clock) string {
+	start := clock.Now()
+	result := make(chan error, 1)
+	go func() { result <- fn(ctx) }()
+	timer := clock.NewTimer(within)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+			return fmt.Sprintf("to return a context error, got %v after %v", err, clock.Now().Sub(start))
+		}
+		return ""
+	case <-timer.C():
+		return fmt.Sprintf("to return within %v, but it did not", within)
+	}
+}
@@ -0,0 +1,102 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type ctxKey struct{}
+
+// noDeadline is a context with the values of the wrapped context, but without its deadline
+type noDeadline struct {
+	context.Context
+}
+
+func (noDeadline) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func TestCheckContextPropagated(t *testing.T) {
+	CheckContextPropagated(func(ctx context.Context, r *ContextRecorder) {
+		r.Record("direct", ctx)
+		sub, cancel := context.WithTimeout(context.WithValue(ctx, ctxKey{}, 1), time.Minute)
+		defer cancel()
+		r.Record("derived", sub)
+	}, t)
+
+	for _, entry := range []func(ctx context.Context, r *ContextRecorder){
+		func(ctx context.Context, r *ContextRecorder) {},
+		func(ctx context.Context, r *ContextRecorder) { r.Record("background", context.Background()) },
+		func(ctx context.Context, r *ContextRecorder) { r.Record("nil", nil) },
+		func(ctx context.Context, r *ContextRecorder) { r.Record("values only", noDeadline{ctx}) },
+	} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckContextPropagated(entry, ft)
+		})
+	}
+
+	m := &messageTB{}
+	CheckContextPropagated(func(ctx context.Context, r *ContextRecorder) {
+		r.Record("ok", ctx)
+		r.Record("", context.TODO())
+	}, m)
+	CheckEqual([]string{"Expected the context to be propagated to all 2 downstream calls, but not to: #1 (not derived from the given context)"}, m.messages, t)
+}
+
+func TestCheckDeadlineRespected(t *testing.T) {
+	CheckDeadlineRespected(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting: %w", ctx.Err())
+		case <-time.After(time.Minute):
+			return nil
+		}
+	}, 50*time.Millisecond, t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDeadlineRespected(func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return ctx.Err()
+		}, 10*time.Millisecond, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDeadlineRespected(func(ctx context.Context) error { return nil }, time.Second, ft)
+	})
+
+	m := &messageTB{}
+	CheckDeadlineRespected(func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}, 10*time.Millisecond, m)
+	CheckEqual([]string{"Expected a call with a context expiring after 20ms to return within 30ms, but it did not"}, m.messages, t)
+}
+
+func TestCheckDeadlineRespectedWith(t *testing.T) {
+	clock := NewFakeClock(clockStart)
+	opts := ClockOptions{Clock: clock}
+	var deadlines []time.Time
+	advanceWhenWaiting(clock, 2, deadlineTimeout)
+	CheckDeadlineRespectedWith(func(ctx context.Context) error {
+		d, _ := ctx.Deadline()
+		deadlines = append(deadlines, d)
+		<-ctx.Done()
+		return ctx.Err()
+	}, time.Second, opts, t)
+	CheckEqual([]time.Time{clockStart.Add(-time.Second), clockStart.Add(deadlineTimeout)}, deadlines, t)
+
+	block := make(chan struct{})
+	defer close(block)
+	m := &messageTB{}
+	advanceWhenWaiting(clock, 2, time.Hour)
+	CheckDeadlineRespectedWith(func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		<-block
+		return nil
+	}, time.Second, opts, m)
+	CheckEqual([]string{"Expected a call with a context expiring after 20ms to return within 1.02s, but it did not"}, m.messages, t)
+}
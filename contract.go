@@ -0,0 +1,35 @@
+package testutils
+
+import "testing"
+
+// Contract is a named suite of behavioral checks that any implementation of T must pass. A package that defines
+// an interface can export a Contract for it, and packages implementing the interface run it with RunContract.
+type Contract[T any] struct {
+	Name  string
+	Cases []ContractCase[T]
+}
+
+// ContractCase is one behavioral check in a Contract. The Check function is given a Tester with the index
+// of the case set, and a new subject to check.
+type ContractCase[T any] struct {
+	Name  string
+	Check func(tt Tester, subject T)
+}
+
+// RunContract runs each case of the contract as a subtest of t, named after the contract and the case. Each
+// case is given a new subject created by the given constructor.
+func RunContract[T any](contract Contract[T], constructor func() T, t *testing.T) {
+	t.Helper()
+	name := contract.Name
+	if name == "" {
+		name = "contract"
+	}
+	t.Run(name, func(t *testing.T) {
+		for i, c := range contract.Cases {
+			i, c := i, c
+			t.Run(c.Name, func(t *testing.T) {
+				c.Check(NewTester(t).At(i), constructor())
+			})
+		}
+	})
+}
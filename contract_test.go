@@ -0,0 +1,35 @@
+package testutils
+
+import (
+	"strings"
+	"testing"
+)
+
+type stringer interface {
+	String() string
+}
+
+type testName string
+
+func (n testName) String() string {
+	return string(n)
+}
+
+func TestRunContract(t *testing.T) {
+	var indexes []int
+	contract := Contract[stringer]{
+		Name: "stringer",
+		Cases: []ContractCase[stringer]{
+			{Name: "not empty", Check: func(tt Tester, s stringer) {
+				indexes = append(indexes, tt.(*tester).indices[0])
+				tt.CheckTrue(s.String() != "")
+			}},
+			{Name: "no spaces", Check: func(tt Tester, s stringer) {
+				indexes = append(indexes, tt.(*tester).indices[0])
+				tt.CheckFalse(strings.Contains(s.String(), " "))
+			}},
+		},
+	}
+	RunContract(contract, func() stringer { return testName("x") }, t)
+	CheckEqual([]int{0, 1}, indexes, t)
+}
@@ -0,0 +1,202 @@
+package testutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// CheckCronNextRuns checks that the next fire times of the given cron expression, computed from (but not including)
+// the time from, are equal to the expected times. As many fire times as there are expected times are computed.
+//
+// The expression is a standard five field cron expression (minute, hour, day of month, month, day of week) where
+// each field may be `*`, a value, a range `a-b`, a step `*/n` or `a-b/n`, or a comma separated list of those.
+// Month and day of week fields also accept three letter names (JAN, MON). The descriptors @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly are also accepted.
+func CheckCronNextRuns(expr string, from time.Time, expected []time.Time, t testing.TB) {
+	t.Helper()
+	sched, err := parseCron(expr)
+	if err != nil {
+		t.Fatalf("CheckCronNextRuns: %s", err.Error())
+		return
+	}
+	got := make([]time.Time, 0, len(expected))
+	next := from
+	for range expected {
+		var ok bool
+		if next, ok = sched.next(next); !ok {
+			break
+		}
+		got = append(got, next)
+	}
+	for i, e := range expected {
+		if i >= len(got) {
+			t.Fatalf("cron %q: expected run [%d] at %v, but there are no more runs, got %v", expr, i, e, got)
+			return
+		}
+		if !e.Equal(got[i]) {
+			t.Fatalf("cron %q: expected run [%d] at %v, got %v (all runs: %v)", expr, i, e, got[i], got)
+			return
+		}
+	}
+}
+
+// cronMaxYears is how far into the future a search for the next run is made before giving up
+const cronMaxYears = 5
+
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type cronField struct {
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{min: 0, max: 59},
+	{min: 0, max: 23},
+	{min: 1, max: 31},
+	{min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func parseCron(expr string) (*cronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if d, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+	parts := strings.Fields(spec)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("cron expression %q must have %d fields, got %d", expr, len(cronFields), len(parts))
+	}
+	bits := make([]uint64, len(parts))
+	for i, p := range parts {
+		b, err := parseCronField(p, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("cron expression %q: %s", expr, err.Error())
+		}
+		bits[i] = b
+	}
+	// Sunday may be given as both 0 and 7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: isCronStar(parts[2]),
+		dowStar: isCronStar(parts[4]),
+	}, nil
+}
+
+// isCronStar returns true if the field starts with `*` or `?`, which includes steps such as `*/2`. As in cron, the
+// day of month and day of week fields both have to match if one of them is such a field, otherwise it is enough
+// that one of them matches.
+func isCronStar(field string) bool {
+	return strings.HasPrefix(field, "*") || strings.HasPrefix(field, "?")
+}
+
+func parseCronField(s string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("illegal step in %q", item)
+			}
+			item = item[:i]
+		}
+		lo, hi := f.min, f.max
+		switch {
+		case item == "*" || item == "?":
+		case strings.IndexByte(item, '-') > 0:
+			i := strings.IndexByte(item, '-')
+			var err error
+			if lo, err = cronValue(item[:i], f); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(item[i+1:], f); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := cronValue(item, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("illegal range in %q", item)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, f cronField) (int, error) {
+	for i, n := range f.names {
+		if strings.EqualFold(s, n) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %q is not in range %d-%d", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domOk := s.dom&(1<<uint(t.Day())) != 0
+	dowOk := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domOk && dowOk
+	}
+	return domOk || dowOk
+}
+
+// next returns the first time after t that matches the schedule and true, or the zero time and false if there
+// is no such time within cronMaxYears.
+func (s *cronSchedule) next(t time.Time) (time.Time, bool) {
+	loc := t.Location()
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.Year() + cronMaxYears
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,60 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckCronNextRuns(t *testing.T) {
+	from := time.Date(2021, 3, 1, 10, 7, 30, 0, time.UTC)
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("*/15 10-11 * * *", from, []time.Time{
+			time.Date(2021, 3, 1, 10, 15, 0, 0, time.UTC),
+			time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC),
+			time.Date(2021, 3, 1, 10, 45, 0, 0, time.UTC),
+			time.Date(2021, 3, 1, 11, 0, 0, 0, time.UTC),
+		}, ft)
+	})
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("0 9 * * MON-FRI", time.Date(2021, 3, 5, 9, 0, 0, 0, time.UTC), []time.Time{
+			time.Date(2021, 3, 8, 9, 0, 0, 0, time.UTC),
+			time.Date(2021, 3, 9, 9, 0, 0, 0, time.UTC),
+		}, ft)
+	})
+
+	// a step over star in day of month or day of week restricts the days as a star does, so both have to match
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("0 0 1 * */2", from, []time.Time{
+			time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		}, ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("0 0 */2 * MON", from, []time.Time{
+			time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 3, 29, 0, 0, 0, 0, time.UTC),
+		}, ft)
+	})
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("@monthly", from, []time.Time{
+			time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		}, ft)
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("0 * * * *", from, []time.Time{
+			time.Date(2021, 3, 1, 10, 30, 0, 0, time.UTC),
+		}, ft)
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("0 0 31 2 *", from, []time.Time{from}, ft)
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckCronNextRuns("61 * * * *", from, nil, ft)
+	})
+}
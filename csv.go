@@ -0,0 +1,133 @@
+package testutils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// CSVOptions control how CheckCSVEqualWith compares CSV data
+type CSVOptions struct {
+	// Comma is the field delimiter, ',' if not set
+	Comma rune
+	// ByHeader makes the first record a header, and columns are matched by header name irrespective of their order
+	ByHeader bool
+	// TrimSpace makes leading and trailing white space of fields insignificant
+	TrimSpace bool
+}
+
+// CheckCSVEqual checks that the expected and got CSV data have the same records. On failure the row and column
+// of the first difference are reported.
+func CheckCSVEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	CheckCSVEqualWith(expected, got, CSVOptions{}, t)
+}
+
+// CheckCSVEqualWith checks that the expected and got CSV data have the same records using the given options. On
+// failure the row and column of the first difference are reported. Rows and columns are numbered from 1 and the
+// row number of the header (when present) is 1.
+func CheckCSVEqualWith(expected, got string, options CSVOptions, t testing.TB) {
+	t.Helper()
+	e, err := parseCSV(expected, options)
+	if err != nil {
+		t.Fatalf("CheckCSVEqual: expected is not valid CSV: %s", err.Error())
+		return
+	}
+	g, err := parseCSV(got, options)
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got error: %s", err.Error())
+		return
+	}
+	if options.ByHeader && len(e) > 0 && len(g) > 0 {
+		if msg := alignCSVColumns(e[0], g); msg != "" {
+			t.Fatalf("Expected CSV columns %s, %s", strings.Join(e[0], ", "), msg)
+			return
+		}
+	}
+	for r := 0; r < len(e) || r < len(g); r++ {
+		if r >= len(g) {
+			t.Fatalf("Expected CSV row %d: %s, got no such row", r+1, strings.Join(e[r], ","))
+			return
+		}
+		if r >= len(e) {
+			t.Fatalf("Expected %d CSV rows, got unexpected row %d: %s", len(e), r+1, strings.Join(g[r], ","))
+			return
+		}
+		if len(e[r]) != len(g[r]) {
+			t.Fatalf("Expected CSV row %d to have %d columns, got %d", r+1, len(e[r]), len(g[r]))
+			return
+		}
+		for c := range e[r] {
+			if e[r][c] != g[r][c] {
+				column := fmt.Sprint(c + 1)
+				if options.ByHeader {
+					column += " (" + e[0][c] + ")"
+				}
+				t.Fatalf("Expected CSV row %d, column %s to be %q, got %q", r+1, column, e[r][c], g[r][c])
+				return
+			}
+		}
+	}
+}
+
+func parseCSV(s string, options CSVOptions) ([][]string, error) {
+	r := csv.NewReader(strings.NewReader(s))
+	if options.Comma != 0 {
+		r.Comma = options.Comma
+	}
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = options.TrimSpace
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if options.TrimSpace {
+		for _, record := range records {
+			for i, f := range record {
+				record[i] = strings.TrimSpace(f)
+			}
+		}
+	}
+	return records, nil
+}
+
+// alignCSVColumns reorders the columns of the got records so that they are in the order of the given header.
+// A description of the missing and extra columns is returned if the headers differ.
+func alignCSVColumns(header []string, got [][]string) string {
+	index := make(map[string]int, len(got[0]))
+	for i, h := range got[0] {
+		index[h] = i
+	}
+	var missing []string
+	order := make([]int, len(header))
+	for i, h := range header {
+		gi, ok := index[h]
+		if !ok {
+			missing = append(missing, h)
+			continue
+		}
+		order[i] = gi
+		delete(index, h)
+	}
+	if len(missing) > 0 || len(index) > 0 {
+		extra := make([]string, 0, len(index))
+		for h := range index {
+			extra = append(extra, h)
+		}
+		sort.Strings(extra)
+		return fmt.Sprintf("missing: [%s], extra: [%s]", strings.Join(missing, ", "), strings.Join(extra, ", "))
+	}
+	for r, record := range got {
+		if len(record) != len(order) {
+			continue
+		}
+		aligned := make([]string, len(order))
+		for i, gi := range order {
+			aligned[i] = record[gi]
+		}
+		got[r] = aligned
+	}
+	return ""
+}
@@ -0,0 +1,42 @@
+package testutils
+
+import "testing"
+
+func TestCheckCSVEqual(t *testing.T) {
+	CheckCSVEqual("a,b\n1,2\n", "a,b\r\n1,2", t)
+	CheckCSVEqual("a,\"b,c\"\n", "\"a\",\"b,c\"\n", t)
+	for _, got := range []string{
+		"a,b\n1,3\n",
+		"a,b\n",
+		"a,b\n1,2\n3,4\n",
+		"a,b\n1,2,3\n",
+		"a,\"b\n",
+	} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckCSVEqual("a,b\n1,2\n", got, ft)
+		})
+	}
+}
+
+func TestCheckCSVEqualWith(t *testing.T) {
+	byHeader := CSVOptions{ByHeader: true, TrimSpace: true}
+	CheckCSVEqualWith("id,name\n1,a\n2,b\n", "name, id\na, 1\n b ,2\n", byHeader, t)
+	CheckCSVEqualWith("id;name\n1;a\n", "id;name\n1;a\n", CSVOptions{Comma: ';'}, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckCSVEqualWith("id,name\n1,a\n", "name,id\na,1\n", CSVOptions{}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckCSVEqualWith("id,name\n1,a\n", "id,name\n1, a\n", CSVOptions{ByHeader: true}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckCSVEqualWith("id,name\n1,a\n", "id,title\n1,a\n", byHeader, ft)
+	})
+
+	m := &messageTB{}
+	CheckCSVEqualWith("id,name\n1,a\n2,b\n", "name,id\na,1\nc,2\n", byHeader, m)
+	CheckEqual([]string{`Expected CSV row 3, column 2 (name) to be "b", got "c"`}, m.messages, t)
+
+	m = &messageTB{}
+	CheckCSVEqualWith("id,name\n", "id,title,x\n", byHeader, m)
+	CheckEqual([]string{"Expected CSV columns id, name, missing: [name], extra: [title, x]"}, m.messages, t)
+}
@@ -0,0 +1,190 @@
+package testutils
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// CheckDeepCopyIndependent checks that the value returned by the clone function (typically a Clone or DeepCopy
+// method) does not share any mutable memory with the original. This is done by mutating every bool, numeric and
+// string value reachable from the clone (including unexported fields, and values reachable via pointers, slices,
+// maps and interfaces), and then asserting that the original is untouched. On failure the paths of all aliased
+// values in the original are reported.
+//
+// Values of types that are shared and immutable by design are neither mutated nor compared, since a clone is
+// expected to share them. These are time.Time (which refers to a shared *time.Location), time.Location,
+// regexp.Regexp, and reflect.Type. Aliasing of memory that is only reachable via such values is not detected.
+func CheckDeepCopyIndependent[T any](original T, clone func(T) T, t testing.TB) {
+	before := map[string]string{}
+	fingerprint(reflect.ValueOf(&original).Elem(), "value", before, map[visit]bool{})
+
+	c := clone(original)
+	mutate(reflect.ValueOf(&c).Elem(), map[visit]bool{})
+
+	after := map[string]string{}
+	fingerprint(reflect.ValueOf(&original).Elem(), "value", after, map[visit]bool{})
+
+	var aliased []string
+	for path, v := range before {
+		if after[path] != v {
+			aliased = append(aliased, path)
+		}
+	}
+	if len(aliased) > 0 {
+		sort.Strings(aliased)
+		t.Helper()
+		t.Fatalf("Expected clone of %T to be independent of the original, but these are aliased: %s", original, strings.Join(aliased, ", "))
+	}
+}
+
+// visit is a value at an address, used to avoid visiting the same value more than once
+type visit struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// sharedImmutableTypes are the types of values that are shared and immutable by design, and that are therefore
+// not followed by fingerprint and mutate
+var sharedImmutableTypes = map[reflect.Type]bool{
+	reflect.TypeOf(time.Time{}):     true,
+	reflect.TypeOf(time.Location{}): true,
+	reflect.TypeOf(regexp.Regexp{}): true,
+}
+
+var reflectTypeType = reflect.TypeOf((*reflect.Type)(nil)).Elem()
+
+// sharedImmutable returns true if v is, or points to, a value that is shared and immutable by design
+func sharedImmutable(v reflect.Value) bool {
+	t := v.Type()
+	if t.Implements(reflectTypeType) {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return sharedImmutableTypes[t]
+}
+
+// settable returns v in a form that can be set, even if it was obtained via unexported struct fields
+func settable(v reflect.Value) reflect.Value {
+	if !v.CanSet() && v.CanAddr() {
+		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	}
+	return v
+}
+
+// fingerprint records the string form of every bool, numeric, and string value reachable from v in out, keyed
+// by its path. Shared immutable values (see sharedImmutable) are not followed.
+func fingerprint(v reflect.Value, path string, out map[string]string, seen map[visit]bool) {
+	if v.IsValid() && sharedImmutable(v) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		key := visit{v.Pointer(), v.Type()}
+		if v.IsNil() || seen[key] {
+			return
+		}
+		seen[key] = true
+		fingerprint(v.Elem(), path, out, seen)
+	case reflect.Interface:
+		if !v.IsNil() {
+			fingerprint(v.Elem(), path, out, seen)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fingerprint(v.Field(i), path+"."+v.Type().Field(i).Name, out, seen)
+		}
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			fingerprint(v.Index(i), fmt.Sprintf("%s[%d]", path, i), out, seen)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			fingerprint(v.MapIndex(k), fmt.Sprintf("%s[%v]", path, k), out, seen)
+		}
+	case reflect.Bool:
+		out[path] = fmt.Sprint(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		out[path] = fmt.Sprint(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		out[path] = fmt.Sprint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		out[path] = fmt.Sprint(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		out[path] = fmt.Sprint(v.Complex())
+	case reflect.String:
+		out[path] = v.String()
+	}
+}
+
+// mutate changes every bool, numeric, and string value reachable from v. Values are only changed once even if
+// they are reachable via multiple paths. Shared immutable values (see sharedImmutable) are not followed.
+func mutate(v reflect.Value, seen map[visit]bool) {
+	if v.IsValid() && sharedImmutable(v) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		key := visit{v.Pointer(), v.Type()}
+		if !v.IsNil() && !seen[key] {
+			seen[key] = true
+			mutate(v.Elem(), seen)
+		}
+		return
+	case reflect.Interface:
+		if !v.IsNil() {
+			mutate(v.Elem(), seen)
+		}
+		return
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			mutate(settable(v.Field(i)), seen)
+		}
+		return
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			mutate(settable(v.Index(i)), seen)
+		}
+		return
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			mv := reflect.New(v.Type().Elem()).Elem()
+			mv.Set(v.MapIndex(k))
+			mutate(mv, seen)
+			v.SetMapIndex(k, mv)
+		}
+		return
+	}
+
+	if !v.CanSet() {
+		return
+	}
+	if v.CanAddr() {
+		key := visit{v.UnsafeAddr(), v.Type()}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(!v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(v.Uint() + 1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(v.Float() + 1)
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(v.Complex() + 1)
+	case reflect.String:
+		v.SetString(v.String() + "~")
+	}
+}
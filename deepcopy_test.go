@@ -0,0 +1,87 @@
+package testutils
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type copyNode struct {
+	Name     string
+	Tags     []string
+	attrs    map[string]int
+	Next     *copyNode
+	Disabled bool
+}
+
+func (n *copyNode) deepCopy() *copyNode {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	c.Tags = append([]string(nil), n.Tags...)
+	c.attrs = make(map[string]int, len(n.attrs))
+	for k, v := range n.attrs {
+		c.attrs[k] = v
+	}
+	c.Next = n.Next.deepCopy()
+	return &c
+}
+
+func (n *copyNode) shallowCopy() *copyNode {
+	c := *n
+	return &c
+}
+
+func TestCheckDeepCopyIndependent(t *testing.T) {
+	original := &copyNode{Name: "a", Tags: []string{"x"}, attrs: map[string]int{"n": 1}, Next: &copyNode{Name: "b"}}
+	original.Next.Next = original
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckDeepCopyIndependent(&copyNode{Name: "a", Tags: []string{"x"}, Next: &copyNode{Name: "b"}}, (*copyNode).deepCopy, ft)
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDeepCopyIndependent(original, (*copyNode).shallowCopy, ft)
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDeepCopyIndependent(original, func(n *copyNode) *copyNode { return n }, ft)
+	})
+}
+
+func Test_mutate(t *testing.T) {
+	original := &copyNode{Name: "a", Tags: []string{"x"}, attrs: map[string]int{"n": 1}, Next: &copyNode{Name: "b"}}
+	before := map[string]string{}
+	fingerprint(reflect.ValueOf(original), "value", before, map[visit]bool{})
+	c := original.shallowCopy()
+	mutate(reflect.ValueOf(c), map[visit]bool{})
+	after := map[string]string{}
+	fingerprint(reflect.ValueOf(original), "value", after, map[visit]bool{})
+	CheckEqual("a", after["value.Name"], t)
+	CheckEqual("x~", after["value.Tags[0]"], t)
+	CheckEqual("2", after["value.attrs[n]"], t)
+	CheckEqual("b~", after["value.Next.Name"], t)
+	CheckEqual("false", after["value.Disabled"], t)
+}
+
+func TestCheckDeepCopyIndependent_sharedImmutable(t *testing.T) {
+	type event struct {
+		Name    string
+		At      time.Time
+		Pattern *regexp.Regexp
+		Type    reflect.Type
+	}
+	loc := time.FixedZone("CET", 3600)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, loc)
+	original := &event{Name: "a", At: at, Pattern: regexp.MustCompile("a+"), Type: reflect.TypeOf(0)}
+	CheckDeepCopyIndependent(original, func(e *event) *event {
+		c := *e
+		return &c
+	}, t)
+	CheckEqual("CET", loc.String(), t)
+	CheckEqual("2024-01-02 03:04:05 +0100 CET", original.At.String(), t)
+	CheckEqual("a+", original.Pattern.String(), t)
+	CheckEqual("int", original.Type.String(), t)
+}
@@ -0,0 +1,50 @@
+package testutils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Integer is a constraint for the integer types used to declare iota based enums
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// EnumRange returns all values from first to last (inclusive) of an iota based enum
+func EnumRange[E Integer](first, last E) []E {
+	var values []E
+	for v := first; v <= last; v++ {
+		values = append(values, v)
+		if v == last {
+			break // avoids overflow when last is the max value of E
+		}
+	}
+	return values
+}
+
+// CheckAllEnumValuesHandled calls handle with each of the given enum values and checks that none of the calls
+// returns an error or panics. This is a runtime complement to exhaustive switch linters; handle typically
+// calls a function with a switch over the enum values that returns an "unknown value" error (or panics) in
+// its default case. On failure all unhandled values are reported together with their error or panic.
+func CheckAllEnumValuesHandled[E any](values []E, handle func(E) error, t testing.TB) {
+	var unhandled []string
+	for _, v := range values {
+		if err := callHandler(v, handle); err != nil {
+			unhandled = append(unhandled, fmt.Sprintf("%v (%s)", v, err.Error()))
+		}
+	}
+	if len(unhandled) > 0 {
+		t.Helper()
+		t.Fatalf("Expected all %d enum values to be handled, %d were not:\n  %s", len(values), len(unhandled), strings.Join(unhandled, "\n  "))
+	}
+}
+
+func callHandler[E any](v E, handle func(E) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handle(v)
+}
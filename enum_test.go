@@ -0,0 +1,53 @@
+package testutils
+
+import (
+	"fmt"
+	"testing"
+)
+
+type testColor int
+
+const (
+	red testColor = iota
+	green
+	blue
+)
+
+func colorName(c testColor) string {
+	switch c {
+	case red:
+		return "red"
+	case green:
+		return "green"
+	default:
+		panic(fmt.Sprintf("unknown color %d", c))
+	}
+}
+
+func TestEnumRange(t *testing.T) {
+	CheckEqual([]testColor{red, green, blue}, EnumRange(red, blue), t)
+	CheckEqual(256, len(EnumRange[uint8](0, 255)), t)
+}
+
+func TestCheckAllEnumValuesHandled(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckAllEnumValuesHandled(EnumRange(red, green), func(c testColor) error {
+			colorName(c)
+			return nil
+		}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckAllEnumValuesHandled(EnumRange(red, blue), func(c testColor) error {
+			colorName(c)
+			return nil
+		}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckAllEnumValuesHandled([]string{"a", "b"}, func(s string) error {
+			if s != "a" {
+				return fmt.Errorf("unknown value %q", s)
+			}
+			return nil
+		}, ft)
+	})
+}
@@ -0,0 +1,140 @@
+package testutils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// CheckErrorsJoined checks that the got error contains all of the expected errors as determined by errors.Is.
+// This is typically used to check an error produced with errors.Join. On failure the missing errors are listed.
+func CheckErrorsJoined(expected []error, got error, t testing.TB) {
+	if missing := missingErrors(expected, got); len(missing) > 0 {
+		t.Helper()
+		t.Fatalf("Expected error %v to contain all of %s, missing: %s", got, listErrors(expected), listErrors(missing))
+	}
+}
+
+// CheckErrorsJoinedExactly checks that the got error contains all of the expected errors as determined by
+// errors.Is, and that each of the joined errors in got (as returned by `Unwrap() []error`, recursively) matches
+// one of the expected errors. On failure the missing and extra errors are listed.
+func CheckErrorsJoinedExactly(expected []error, got error, t testing.TB) {
+	missing := missingErrors(expected, got)
+	var extra []error
+	for _, m := range joinedErrors(got) {
+		found := false
+		for _, e := range expected {
+			if errors.Is(m, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			extra = append(extra, m)
+		}
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		t.Helper()
+		t.Fatalf("Expected error %v to contain exactly %s, missing: %s, extra: %s", got, listErrors(expected), listErrors(missing), listErrors(extra))
+	}
+}
+
+func missingErrors(expected []error, got error) []error {
+	var missing []error
+	for _, e := range expected {
+		if !errors.Is(got, e) {
+			missing = append(missing, e)
+		}
+	}
+	return missing
+}
+
+// joinedErrors returns the leaf members of a joined error. An error that is not joined is its own only member.
+func joinedErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	j, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	var members []error
+	for _, m := range j.Unwrap() {
+		members = append(members, joinedErrors(m)...)
+	}
+	return members
+}
+
+func listErrors(errs []error) string {
+	s := make([]string, len(errs))
+	for i, e := range errs {
+		s[i] = fmt.Sprintf("%q", e)
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+// FieldError is a validation error for a field, as compared by CheckValidationErrors
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// String returns the field and the message separated by a colon
+func (fe FieldError) String() string {
+	return fe.Field + ": " + fe.Message
+}
+
+// FieldErrors returns the members of a joined error (see CheckErrorsJoinedExactly) as field errors. The field of
+// a member that has a `Field() string` method is the result of that method, and the field of other members is
+// empty. The message is the Error() of the member.
+func FieldErrors(err error) []FieldError {
+	members := joinedErrors(err)
+	result := make([]FieldError, len(members))
+	for i, m := range members {
+		result[i].Message = m.Error()
+		if f, ok := m.(interface{ Field() string }); ok {
+			result[i].Field = f.Field()
+		}
+	}
+	return result
+}
+
+// CheckValidationErrors checks that the got field errors are the same as the expected field errors, irrespective
+// of order. This is typically used with validators that return a list of violations. The same field may have
+// several errors. On failure the missing and the unexpected errors are listed separately, sorted by field.
+func CheckValidationErrors(expected, got []FieldError, t testing.TB) {
+	counts := make(map[FieldError]int, len(expected))
+	for _, e := range expected {
+		counts[e]++
+	}
+	var unexpected []string
+	for _, g := range got {
+		if counts[g] == 0 {
+			unexpected = append(unexpected, g.String())
+			continue
+		}
+		counts[g]--
+	}
+	var missing []string
+	for _, e := range expected {
+		if counts[e] > 0 {
+			missing = append(missing, e.String())
+			counts[e]--
+		}
+	}
+	if len(missing) > 0 || len(unexpected) > 0 {
+		sort.Strings(missing)
+		sort.Strings(unexpected)
+		var sb strings.Builder
+		for _, m := range missing {
+			sb.WriteString("\n  missing: " + m)
+		}
+		for _, u := range unexpected {
+			sb.WriteString("\n  unexpected: " + u)
+		}
+		t.Helper()
+		t.Fatalf("Expected validation errors to match, but:%s", sb.String())
+	}
+}
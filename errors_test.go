@@ -0,0 +1,69 @@
+package testutils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestCheckErrorsJoined(t *testing.T) {
+	joined := errors.Join(io.EOF, fmt.Errorf("wrapped: %w", os.ErrNotExist))
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckErrorsJoined([]error{io.EOF, os.ErrNotExist}, joined, ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckErrorsJoined([]error{io.EOF}, joined, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckErrorsJoined([]error{io.EOF, os.ErrExist}, joined, ft)
+	})
+}
+
+func TestCheckErrorsJoinedExactly(t *testing.T) {
+	joined := errors.Join(io.EOF, errors.Join(os.ErrNotExist, os.ErrClosed))
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckErrorsJoinedExactly([]error{os.ErrClosed, io.EOF, os.ErrNotExist}, joined, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckErrorsJoinedExactly([]error{io.EOF, os.ErrNotExist}, joined, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckErrorsJoinedExactly([]error{io.EOF}, nil, ft)
+	})
+}
+
+type testFieldError struct {
+	field, msg string
+}
+
+func (e testFieldError) Error() string { return e.msg }
+func (e testFieldError) Field() string { return e.field }
+
+func TestCheckValidationErrors(t *testing.T) {
+	got := FieldErrors(errors.Join(
+		testFieldError{"name", "is required"},
+		testFieldError{"age", "must be positive"},
+		testFieldError{"age", "must be an integer"},
+		io.EOF))
+	CheckValidationErrors([]FieldError{
+		{"age", "must be an integer"},
+		{"", "EOF"},
+		{"name", "is required"},
+		{"age", "must be positive"},
+	}, got, t)
+
+	m := &messageTB{}
+	CheckValidationErrors([]FieldError{
+		{"name", "is required"},
+		{"name", "is required"},
+		{"email", "is invalid"},
+		{"age", "must be positive"},
+	}, got, m)
+	CheckEqual([]string{"Expected validation errors to match, but:\n" +
+		"  missing: email: is invalid\n" +
+		"  missing: name: is required\n" +
+		"  unexpected: : EOF\n" +
+		"  unexpected: age: must be an integer"}, m.messages, t)
+}
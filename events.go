@@ -0,0 +1,91 @@
+package testutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Event is an event recorded by an EventLog
+type Event struct {
+	Time time.Time
+	Name string
+	// Fields holds alternating keys and values
+	Fields []interface{}
+}
+
+// EventLog is an event sink that records a transcript of events, typically from a fake or a hook in the code under
+// test, to be compared with a golden file by CheckEventLogGolden. The zero value is ready to use, and it is safe
+// for use by multiple goroutines.
+type EventLog struct {
+	// Clock gives the time of recorded events, SystemClock if not set
+	Clock Clock
+
+	lock   sync.Mutex
+	events []Event
+}
+
+// Record records an event with the given name and fields given as alternating keys and values, i.e.
+// log.Record("request", "method", "GET", "status", 200).
+func (l *EventLog) Record(name string, keyValues ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := time.Now()
+	if l.Clock != nil {
+		now = l.Clock.Now()
+	}
+	l.events = append(l.events, Event{Time: now, Name: name, Fields: keyValues})
+}
+
+// Events returns the recorded events in the order they were recorded
+func (l *EventLog) Events() []Event {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return append([]Event(nil), l.events...)
+}
+
+// Transcript returns the recorded events with one line per event, consisting of the time (in RFC 3339 format), the
+// name, and the fields formatted as key=value. String values that contain spaces, quotes, or '=' are quoted, and
+// time.Time values are formatted in RFC 3339 format.
+func (l *EventLog) Transcript() string {
+	var sb strings.Builder
+	for _, e := range l.Events() {
+		sb.WriteString(e.Time.Format(time.RFC3339Nano))
+		sb.WriteString(" ")
+		sb.WriteString(e.Name)
+		for i := 0; i < len(e.Fields); i += 2 {
+			var value interface{} = "<missing>"
+			if i+1 < len(e.Fields) {
+				value = e.Fields[i+1]
+			}
+			fmt.Fprintf(&sb, " %v=%s", e.Fields[i], eventValue(value))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func eventValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		if v == "" || strings.ContainsAny(v, " \t\n\"=") {
+			return strconv.Quote(v)
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// CheckEventLogGolden compares the transcript of the event log (see EventLog.Transcript) with the content of the
+// given golden file as CheckGolden does, including updating it in update mode (see UpdateGolden). Timestamps in the
+// transcript (the time of each event and time values in fields) are masked with <TIMESTAMP> before the comparison,
+// which gives readable and stable regression tests for complex interaction sequences.
+func CheckEventLogGolden(filename string, log *EventLog, t testing.TB) {
+	t.Helper()
+	CheckGolden(filename, RedactTimestamps(log.Transcript()), t)
+}
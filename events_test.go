@@ -0,0 +1,36 @@
+package testutils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEventLog(t *testing.T) {
+	var log EventLog
+	log.Record("connect", "addr", "db:5432")
+	log.Record("query", "sql", "SELECT 1", "rows", 1, "at", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	log.Record("close", "reason")
+	CheckEqual(3, len(log.Events()), t)
+	CheckEqual("query", log.Events()[1].Name, t)
+	CheckMatches(`^\d{4}-\d\d-\d\dT\S+ connect addr=db:5432\n`+
+		`\S+ query sql="SELECT 1" rows=1 at=2024-01-02T03:04:05Z\n`+
+		`\S+ close reason=<missing>\n$`, log.Transcript(), t)
+}
+
+func TestCheckEventLogGolden(t *testing.T) {
+	var log EventLog
+	log.Record("start", "id", 1)
+	log.Record("timeout", "after", time.Second, "deadline", time.Now())
+	log.Record("stop", "id", 1)
+	CheckEventLogGolden(filepath.Join("testdata", "events.golden"), &log, t)
+}
+
+func TestEventLog_Clock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	log := EventLog{Clock: clock}
+	log.Record("a")
+	clock.Advance(time.Second)
+	log.Record("b")
+	CheckEqual("2024-01-02T03:04:05Z a\n2024-01-02T03:04:06Z b\n", log.Transcript(), t)
+}
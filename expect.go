@@ -0,0 +1,237 @@
+package testutils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// The Expect functions in this file are non-fatal counterparts of the most commonly used Check functions. They
+// report a failure with t.Errorf instead of t.Fatalf which makes the test continue, and a single test can then
+// report multiple independent failures instead of stopping at the first. Checks without an Expect counterpart are
+// made non-fatal by passing them NonFatal(t).
+
+// NonFatal returns a testing.TB that reports calls to Fatal, Fatalf and FailNow as calls to Error, Errorf, and Fail
+// on the given t. Any check can be made non-fatal by passing it the result of NonFatal(t).
+func NonFatal(t testing.TB) testing.TB {
+	if nf, ok := t.(nonFatal); ok {
+		return nf
+	}
+	return nonFatal{t}
+}
+
+type nonFatal struct {
+	testing.TB
+}
+
+func (nf nonFatal) Fatal(args ...interface{}) {
+	nf.TB.Helper()
+	nf.TB.Error(args...)
+}
+
+func (nf nonFatal) Fatalf(format string, args ...interface{}) {
+	nf.TB.Helper()
+	nf.TB.Errorf(format, args...)
+}
+
+func (nf nonFatal) FailNow() {
+	nf.TB.Fail()
+}
+
+// ExpectEqual is the non-fatal version of CheckEqual
+func ExpectEqual(expected interface{}, got interface{}, t testing.TB) {
+	t.Helper()
+	CheckEqual(expected, got, NonFatal(t))
+}
+
+// ExpectNotEqual is the non-fatal version of CheckNotEqual
+func ExpectNotEqual(expected interface{}, got interface{}, t testing.TB) {
+	t.Helper()
+	CheckNotEqual(expected, got, NonFatal(t))
+}
+
+// ExpectMatches is the non-fatal version of CheckMatches
+func ExpectMatches(expected interface{}, got string, t testing.TB) {
+	t.Helper()
+	CheckMatches(expected, got, NonFatal(t))
+}
+
+// ExpectReflectEqual is the non-fatal version of CheckReflectEqual
+func ExpectReflectEqual(expected, got reflect.Value, t testing.TB) {
+	t.Helper()
+	CheckReflectEqual(expected, got, NonFatal(t))
+}
+
+// ExpectEqualAndNoError is the non-fatal version of CheckEqualAndNoError
+func ExpectEqualAndNoError(expected interface{}, got interface{}, gotError error, t testing.TB) {
+	t.Helper()
+	CheckEqualAndNoError(expected, got, gotError, NonFatal(t))
+}
+
+// ExpectContainsElements is the non-fatal version of CheckContainsElements
+func ExpectContainsElements(expected interface{}, got interface{}, t testing.TB) {
+	t.Helper()
+	CheckContainsElements(expected, got, NonFatal(t))
+}
+
+// ExpectEqualElements is the non-fatal version of CheckEqualElements
+func ExpectEqualElements(expected interface{}, got interface{}, t testing.TB) {
+	t.Helper()
+	CheckEqualElements(expected, got, NonFatal(t))
+}
+
+// ExpectTextEqual is the non-fatal version of CheckTextEqual
+func ExpectTextEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	CheckTextEqual(expected, got, NonFatal(t))
+}
+
+// ExpectStringSlicesEqual is the non-fatal version of CheckStringSlicesEqual
+func ExpectStringSlicesEqual(expected, got []string, t testing.TB) {
+	t.Helper()
+	CheckStringSlicesEqual(expected, got, NonFatal(t))
+}
+
+// ExpectTimeWithin is the non-fatal version of CheckTimeWithin
+func ExpectTimeWithin(expected, got time.Time, delta time.Duration, t testing.TB) {
+	t.Helper()
+	CheckTimeWithin(expected, got, delta, NonFatal(t))
+}
+
+// ExpectTimesEqual is the non-fatal version of CheckTimesEqual
+func ExpectTimesEqual(expected, got time.Time, t testing.TB) {
+	t.Helper()
+	CheckTimesEqual(expected, got, NonFatal(t))
+}
+
+// ExpectNil is the non-fatal version of CheckNil
+func ExpectNil(got interface{}, t testing.TB) {
+	t.Helper()
+	CheckNil(got, NonFatal(t))
+}
+
+// ExpectNotNil is the non-fatal version of CheckNotNil
+func ExpectNotNil(got interface{}, t testing.TB) {
+	t.Helper()
+	CheckNotNil(got, NonFatal(t))
+}
+
+// ExpectError is the non-fatal version of CheckError
+func ExpectError(got interface{}, t testing.TB) {
+	t.Helper()
+	CheckError(got, NonFatal(t))
+}
+
+// ExpectNotError is the non-fatal version of CheckNotError
+func ExpectNotError(got interface{}, t testing.TB) {
+	t.Helper()
+	CheckNotError(got, NonFatal(t))
+}
+
+// ExpectNumericGreater is the non-fatal version of CheckNumericGreater
+func ExpectNumericGreater(expected interface{}, got interface{}, t testing.TB) {
+	t.Helper()
+	CheckNumericGreater(expected, got, NonFatal(t))
+}
+
+// ExpectNumericLess is the non-fatal version of CheckNumericLess
+func ExpectNumericLess(expected interface{}, got interface{}, t testing.TB) {
+	t.Helper()
+	CheckNumericLess(expected, got, NonFatal(t))
+}
+
+// ExpectTrue is the non-fatal version of CheckTrue
+func ExpectTrue(got bool, t testing.TB) {
+	t.Helper()
+	CheckTrue(got, NonFatal(t))
+}
+
+// ExpectFalse is the non-fatal version of CheckFalse
+func ExpectFalse(got bool, t testing.TB) {
+	t.Helper()
+	CheckFalse(got, NonFatal(t))
+}
+
+// ExpectFilesEqual is the non-fatal version of CheckFilesEqual
+func ExpectFilesEqual(file1, file2 string, t testing.TB) {
+	t.Helper()
+	CheckFilesEqual(file1, file2, NonFatal(t))
+}
+
+// ExpectFileExists is the non-fatal version of CheckFileExists
+func ExpectFileExists(filename string, t testing.TB) {
+	t.Helper()
+	CheckFileExists(filename, NonFatal(t))
+}
+
+// ExpectFileNotExists is the non-fatal version of CheckFileNotExists
+func ExpectFileNotExists(filename string, t testing.TB) {
+	t.Helper()
+	CheckFileNotExists(filename, NonFatal(t))
+}
+
+// ExpectDirExists is the non-fatal version of CheckDirExists
+func ExpectDirExists(dirname string, t testing.TB) {
+	t.Helper()
+	CheckDirExists(dirname, NonFatal(t))
+}
+
+// ExpectDirEmpty is the non-fatal version of CheckDirEmpty
+func ExpectDirEmpty(dirname string, t testing.TB) {
+	t.Helper()
+	CheckDirEmpty(dirname, NonFatal(t))
+}
+
+// ExpectEqualT is the non-fatal version of CheckEqualT
+func ExpectEqualT[T comparable](expected, got T, t testing.TB) {
+	t.Helper()
+	CheckEqualT(expected, got, NonFatal(t))
+}
+
+// ExpectNotEqualT is the non-fatal version of CheckNotEqualT
+func ExpectNotEqualT[T comparable](expected, got T, t testing.TB) {
+	t.Helper()
+	CheckNotEqualT(expected, got, NonFatal(t))
+}
+
+// ExpectSliceEqualT is the non-fatal version of CheckSliceEqualT
+func ExpectSliceEqualT[T comparable](expected, got []T, t testing.TB) {
+	t.Helper()
+	CheckSliceEqualT(expected, got, NonFatal(t))
+}
+
+// ExpectEqualElementsT is the non-fatal version of CheckEqualElementsT
+func ExpectEqualElementsT[T comparable](expected, got []T, t testing.TB) {
+	t.Helper()
+	CheckEqualElementsT(expected, got, NonFatal(t))
+}
+
+// ExpectMapEqualT is the non-fatal version of CheckMapEqualT
+func ExpectMapEqualT[K, V comparable](expected, got map[K]V, t testing.TB) {
+	t.Helper()
+	CheckMapEqualT(expected, got, NonFatal(t))
+}
+
+// ExpectMapEqualFuncT is the non-fatal version of CheckMapEqualFuncT
+func ExpectMapEqualFuncT[K comparable, V any](expected, got map[K]V, equal func(a, b V) bool, t testing.TB) {
+	t.Helper()
+	CheckMapEqualFuncT(expected, got, equal, NonFatal(t))
+}
+
+// ExpectCronNextRuns is the non-fatal version of CheckCronNextRuns
+func ExpectCronNextRuns(expr string, from time.Time, expected []time.Time, t testing.TB) {
+	t.Helper()
+	CheckCronNextRuns(expr, from, expected, NonFatal(t))
+}
+
+// ExpectRateLimited is the non-fatal version of CheckRateLimited
+func ExpectRateLimited(n, k int, window time.Duration, call func() bool, t testing.TB) {
+	t.Helper()
+	CheckRateLimited(n, k, window, call, NonFatal(t))
+}
+
+// ExpectBackoffSequence is the non-fatal version of CheckBackoffSequence
+func ExpectBackoffSequence(b Backoff, expected []time.Duration, jitter float64, t testing.TB) {
+	t.Helper()
+	CheckBackoffSequence(b, expected, jitter, NonFatal(t))
+}
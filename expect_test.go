@@ -0,0 +1,56 @@
+package testutils
+
+import "testing"
+
+func TestExpectEqual(t *testing.T) {
+	continued := false
+	ensureFailed(t, func(ft *testing.T) {
+		ExpectEqual("a", "b", ft)
+		continued = true
+	})
+	CheckTrue(continued, t)
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		ExpectEqual("a", "a", ft)
+	})
+}
+
+func TestExpectMatches(t *testing.T) {
+	continued := false
+	ensureFailed(t, func(ft *testing.T) {
+		ExpectMatches("[", "a", ft)
+		continued = true
+	})
+	CheckTrue(continued, t)
+}
+
+func TestNonFatal(t *testing.T) {
+	continued := false
+	ensureFailed(t, func(ft *testing.T) {
+		nf := NonFatal(ft)
+		nf.FailNow()
+		nf.Fatal("still going")
+		continued = true
+	})
+	CheckTrue(continued, t)
+}
+
+func TestTester_Expect(t *testing.T) {
+	continued := false
+	ensureFailed(t, func(ft *testing.T) {
+		tt := NewTester(ft)
+		tt.Expect().At(1).CheckEqual(1, 2)
+		tt.Expect().CheckTruef(false, "not %s", "true")
+		continued = true
+	})
+	CheckTrue(continued, t)
+
+	continued = false
+	ensureFailed(t, func(ft *testing.T) {
+		tt := NewTester(ft)
+		tt.Expect()
+		tt.CheckEqual(1, 2)
+		continued = true
+	})
+	CheckFalse(continued, t)
+}
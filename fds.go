@@ -0,0 +1,115 @@
+package testutils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fdDir returns the directory that lists the open file descriptors of the process, or an empty string if the
+// platform does not have one
+func fdDir() string {
+	switch runtime.GOOS {
+	case "linux", "android":
+		return "/proc/self/fd"
+	case "darwin", "freebsd", "netbsd", "openbsd", "dragonfly":
+		return "/dev/fd"
+	}
+	return ""
+}
+
+// VerifyNoFDLeaks records the open file descriptors of the process when it is called, and registers a cleanup
+// function that checks that no other descriptors are open when the test finishes. This detects files and sockets
+// that are not closed. Descriptors are given some time to be closed before they are considered leaked. On Linux
+// the leaked descriptors are reported with what they refer to (i.e. a file path or "socket:[1234]"). The check is
+// skipped (with a log message) on platforms where the open descriptors cannot be listed, such as Windows.
+//
+// Tests that use VerifyNoFDLeaks should not run in parallel since their descriptors cannot be told apart.
+func VerifyNoFDLeaks(t testing.TB) {
+	t.Helper()
+	before, err := openFDs()
+	if err != nil {
+		t.Logf("VerifyNoFDLeaks: unable to list open file descriptors: %s", err.Error())
+		return
+	}
+	t.Cleanup(func() {
+		t.Helper()
+		checkNoFDLeaks(before, leakWait, t)
+	})
+}
+
+func checkNoFDLeaks(before map[int]string, wait time.Duration, t testing.TB) {
+	deadline := time.Now().Add(wait)
+	for {
+		fds, err := openFDs()
+		if err != nil {
+			t.Helper()
+			t.Fatal(err)
+			return
+		}
+		var leaked []string
+		for _, fd := range sortedFDs(fds) {
+			if target, ok := before[fd]; !ok || target != fds[fd] {
+				leaked = append(leaked, fdText(fd, fds[fd]))
+			}
+		}
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Helper()
+			t.Fatalf("Expected no leaked file descriptors, found %d: %s", len(leaked), strings.Join(leaked, ", "))
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// openFDs returns the open file descriptors of the process mapped to what they refer to, if known
+func openFDs() (map[int]string, error) {
+	dir := fdDir()
+	if dir == "" {
+		return nil, errors.New("not supported on " + runtime.GOOS)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	fds := make(map[int]string, len(entries))
+	for _, e := range entries {
+		fd, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		target, err := os.Readlink(filepath.Join(dir, e.Name()))
+		if errors.Is(err, fs.ErrNotExist) {
+			// the descriptor used to read the directory is closed
+			continue
+		}
+		fds[fd] = target
+	}
+	return fds, nil
+}
+
+func sortedFDs(fds map[int]string) []int {
+	result := make([]int, 0, len(fds))
+	for fd := range fds {
+		result = append(result, fd)
+	}
+	sort.Ints(result)
+	return result
+}
+
+func fdText(fd int, target string) string {
+	if target == "" {
+		return strconv.Itoa(fd)
+	}
+	return strconv.Itoa(fd) + " (" + target + ")"
+}
@@ -0,0 +1,41 @@
+package testutils
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestVerifyNoFDLeaks(t *testing.T) {
+	VerifyNoFDLeaks(t)
+	f, err := os.Create(filepath.Join(t.TempDir(), "a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+}
+
+func Test_checkNoFDLeaks(t *testing.T) {
+	before, err := openFDs()
+	if err != nil {
+		t.Skip(err)
+	}
+	name := filepath.Join(t.TempDir(), "leaked.txt")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	m := &messageTB{}
+	checkNoFDLeaks(before, 20*time.Millisecond, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected no leaked file descriptors, found 1: \d+`, m.messages[0], t)
+	if fdDir() == "/proc/self/fd" {
+		CheckMatches(regexp.QuoteMeta("("+name+")")+`$`, m.messages[0], t)
+	}
+	f.Close()
+	checkNoFDLeaks(before, 0, t)
+}
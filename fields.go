@@ -0,0 +1,219 @@
+package testutils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// CheckAllFieldsSet checks that no exported field of the given struct (or pointer to struct) holds its zero value.
+// This is useful to verify that mappers and converters copy every field. Nested structs (and pointers to structs)
+// are checked field by field, except time.Time which is checked as a value. Fields that are allowed to be zero are
+// given as dotted paths in allowZero, e.g. "Address.Line2". Allowing a struct field allows all of its fields.
+// On failure the paths of all zero fields are reported.
+func CheckAllFieldsSet(value interface{}, allowZero []string, t testing.TB) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		t.Helper()
+		t.Fatalf("CheckAllFieldsSet: expected a struct or pointer to struct, got %T", value)
+		return
+	}
+	allowed := make(map[string]bool, len(allowZero))
+	for _, a := range allowZero {
+		allowed[a] = true
+	}
+	var zero []string
+	zeroFields(v, "", allowed, &zero)
+	if len(zero) > 0 {
+		t.Helper()
+		t.Fatalf("Expected all fields of %T to be set, these have zero values: %s", value, strings.Join(zero, ", "))
+	}
+}
+
+func zeroFields(v reflect.Value, path string, allowed map[string]bool, zero *[]string) {
+	vt := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		sf := vt.Field(i)
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		fp := sf.Name
+		if path != "" {
+			fp = path + "." + sf.Name
+		}
+		if allowed[fp] {
+			continue
+		}
+		f := v.Field(i)
+		if f.Kind() == reflect.Ptr && !f.IsNil() && f.Elem().Kind() == reflect.Struct && f.Type().Elem() != timeType {
+			f = f.Elem()
+		}
+		if f.Kind() == reflect.Struct && f.Type() != timeType {
+			zeroFields(f, fp, allowed, zero)
+			continue
+		}
+		if f.IsZero() {
+			*zero = append(*zero, fp)
+		}
+	}
+}
+
+// CheckFieldsCovered checks that every exported field of the source type S influences the result of the mapping
+// function. This catches copy-paste mappers that drop fields. Starting from the given base value, each field is
+// perturbed in turn (nested structs field by field) and the mapping is called with the perturbed value. A field
+// is not covered if the result is equal to the result of mapping the base value. Fields that are not expected to
+// influence the result are given as dotted paths in ignore. On failure the paths of all uncovered fields are reported.
+//
+// S may be a struct or a pointer to a struct, in which case the perturbed values are pointers to copies of the
+// struct, and the base struct is not modified. Fields holding functions, channels, or nil interfaces cannot be
+// perturbed and are reported as uncovered unless ignored.
+func CheckFieldsCovered[S, D any](base S, mapping func(S) D, ignore []string, t testing.TB) {
+	bv := reflect.ValueOf(&base).Elem()
+	isPtr := bv.Kind() == reflect.Ptr
+	st := bv.Type()
+	if isPtr {
+		st = st.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		t.Helper()
+		t.Fatalf("CheckFieldsCovered: expected a struct or pointer to struct, got %s", bv.Type())
+		return
+	}
+	if isPtr && bv.IsNil() {
+		t.Helper()
+		t.Fatalf("CheckFieldsCovered: expected a non nil %s", bv.Type())
+		return
+	}
+	ignored := make(map[string]bool, len(ignore))
+	for _, i := range ignore {
+		ignored[i] = true
+	}
+	baseResult := reflect.ValueOf(mapping(base))
+
+	var paths []string
+	sourceFields(st, "", ignored, &paths)
+	var uncovered []string
+	for _, path := range paths {
+		s := base
+		f := reflect.ValueOf(&s).Elem()
+		if isPtr {
+			// perturb a copy of the struct
+			c := reflect.New(st)
+			c.Elem().Set(f.Elem())
+			f.Set(c)
+			f = c.Elem()
+		}
+		for _, name := range strings.Split(path, ".") {
+			f = f.FieldByName(name)
+		}
+		if !perturb(f) {
+			uncovered = append(uncovered, path)
+			continue
+		}
+		if _, differs := firstDifference(baseResult, reflect.ValueOf(mapping(s)), "result"); !differs {
+			uncovered = append(uncovered, path)
+		}
+	}
+	if len(uncovered) > 0 {
+		t.Helper()
+		t.Fatalf("Expected all fields of %T to influence the mapped %T, these do not: %s", base, *new(D), strings.Join(uncovered, ", "))
+	}
+}
+
+// sourceFields appends the dotted paths of all exported fields of the struct type st (nested structs field by
+// field) to paths.
+func sourceFields(st reflect.Type, path string, ignored map[string]bool, paths *[]string) {
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		fp := sf.Name
+		if path != "" {
+			fp = path + "." + sf.Name
+		}
+		if ignored[fp] {
+			continue
+		}
+		if sf.Type.Kind() == reflect.Struct && sf.Type != timeType {
+			sourceFields(sf.Type, fp, ignored, paths)
+			continue
+		}
+		*paths = append(*paths, fp)
+	}
+}
+
+// perturb changes the settable value v to a different value without modifying any memory that v may share with
+// other values, and returns true, or returns false if v cannot be changed.
+func perturb(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr:
+		n := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			n.Elem().Set(v.Elem())
+			if !perturb(n.Elem()) {
+				return false
+			}
+		}
+		v.Set(n)
+	case reflect.Interface:
+		if v.IsNil() {
+			return false
+		}
+		n := reflect.New(v.Elem().Type()).Elem()
+		n.Set(v.Elem())
+		if !perturb(n) {
+			return false
+		}
+		v.Set(n)
+	case reflect.Slice:
+		n := reflect.MakeSlice(v.Type(), v.Len(), v.Len()+1)
+		reflect.Copy(n, v)
+		v.Set(reflect.Append(n, reflect.Zero(v.Type().Elem())))
+	case reflect.Map:
+		n := reflect.MakeMap(v.Type())
+		keys := v.MapKeys()
+		if len(keys) > 0 {
+			// drop the first key
+			for _, k := range keys[1:] {
+				n.SetMapIndex(k, v.MapIndex(k))
+			}
+		} else {
+			k := reflect.New(v.Type().Key()).Elem()
+			if !perturb(k) {
+				return false
+			}
+			n.SetMapIndex(k, reflect.Zero(v.Type().Elem()))
+		}
+		v.Set(n)
+	case reflect.Struct:
+		if v.Type() == timeType {
+			v.Set(reflect.ValueOf(v.Interface().(time.Time).Add(time.Hour)))
+			return true
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() && perturb(f) {
+				return true
+			}
+		}
+		return false
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if perturb(v.Index(i)) {
+				return true
+			}
+		}
+		return false
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		mutate(v, map[visit]bool{})
+	default:
+		return false
+	}
+	return true
+}
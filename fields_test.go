@@ -0,0 +1,100 @@
+package testutils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type fieldsAddress struct {
+	Line1 string
+	Line2 string
+}
+
+type fieldsPerson struct {
+	Name    string
+	Age     int
+	Born    time.Time
+	Address *fieldsAddress
+	Tags    []string
+	secret  string
+}
+
+func TestCheckAllFieldsSet(t *testing.T) {
+	p := fieldsPerson{
+		Name:    "Bob",
+		Age:     42,
+		Born:    time.Now(),
+		Address: &fieldsAddress{Line1: "Main Street"},
+		Tags:    []string{"a"},
+	}
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckAllFieldsSet(p, []string{"Address.Line2"}, ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckAllFieldsSet(&p, []string{"Address"}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckAllFieldsSet(p, nil, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckAllFieldsSet(fieldsPerson{Name: "x"}, nil, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckAllFieldsSet("x", nil, ft)
+	})
+
+	var zero []string
+	zeroFields(reflect.ValueOf(fieldsPerson{Age: 1}), "", map[string]bool{"Tags": true}, &zero)
+	CheckEqual([]string{"Name", "Born", "Address"}, zero, t)
+}
+
+type fieldsPersonDTO struct {
+	FullName string
+	Age      int
+	Street   string
+	Labels   []string
+}
+
+func TestCheckFieldsCovered(t *testing.T) {
+	good := func(p fieldsPerson) fieldsPersonDTO {
+		d := fieldsPersonDTO{FullName: p.Name, Age: p.Age, Labels: p.Tags}
+		if p.Address != nil {
+			d.Street = p.Address.Line1 + p.Address.Line2
+		}
+		return d
+	}
+	bad := func(p fieldsPerson) fieldsPersonDTO {
+		return fieldsPersonDTO{FullName: p.Name, Age: p.Age}
+	}
+	base := fieldsPerson{Name: "Bob", Tags: []string{"x"}, Address: &fieldsAddress{}}
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckFieldsCovered(base, good, []string{"Born"}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFieldsCovered(base, good, nil, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFieldsCovered(base, bad, []string{"Born"}, ft)
+	})
+	CheckEqual([]string{"x"}, base.Tags, t)
+	CheckEqual("", base.Address.Line1, t)
+
+	goodPtr := func(p *fieldsPerson) fieldsPersonDTO { return good(*p) }
+	badPtr := func(p *fieldsPerson) fieldsPersonDTO { return bad(*p) }
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckFieldsCovered(&base, goodPtr, []string{"Born"}, ft)
+	})
+	m := &messageTB{}
+	CheckFieldsCovered(&base, badPtr, []string{"Born"}, m)
+	CheckFieldsCovered((*fieldsPerson)(nil), goodPtr, nil, m)
+	CheckFieldsCovered("x", func(s string) string { return s }, nil, m)
+	CheckEqual([]string{
+		"Expected all fields of *testutils.fieldsPerson to influence the mapped testutils.fieldsPersonDTO, these do not: Address, Tags",
+		"CheckFieldsCovered: expected a non nil *testutils.fieldsPerson",
+		"CheckFieldsCovered: expected a struct or pointer to struct, got string",
+	}, m.messages, t)
+	CheckEqual("Bob", base.Name, t)
+	CheckEqual([]string{"x"}, base.Tags, t)
+}
@@ -0,0 +1,457 @@
+package testutils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// CheckGlobMatches checks that the set of files matching the given glob pattern under the given root directory is
+// equal to the expected set of paths. The pattern has the syntax of filepath.Match and is relative to root, and the
+// expected paths are relative to root and use '/' as the separator irrespective of OS. The order of the expected
+// paths does not matter. On failure the missing and the unexpected paths are reported.
+func CheckGlobMatches(root, pattern string, expected []string, t testing.TB) {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(root, pattern))
+	if err != nil {
+		t.Fatalf("CheckGlobMatches: illegal pattern %q: %s", pattern, err.Error())
+		return
+	}
+	found := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		rel, err := filepath.Rel(root, m)
+		if err != nil {
+			t.Fatal(err)
+			return
+		}
+		found[filepath.ToSlash(rel)] = true
+	}
+	var missing []string
+	for _, e := range expected {
+		e = filepath.ToSlash(filepath.Clean(e))
+		if found[e] {
+			delete(found, e)
+		} else {
+			missing = append(missing, e)
+		}
+	}
+	if len(missing) > 0 || len(found) > 0 {
+		unexpected := make([]string, 0, len(found))
+		for f := range found {
+			unexpected = append(unexpected, f)
+		}
+		sort.Strings(missing)
+		sort.Strings(unexpected)
+		t.Fatalf("Expected files matching %q under %q, missing: [%s], unexpected: [%s]",
+			pattern, root, strings.Join(missing, ", "), strings.Join(unexpected, ", "))
+	}
+}
+
+// CheckDirsEqual checks that the two directory trees have the same structure, with the same names of files and
+// directories, and that files with the same relative path have the same content. On failure all missing and extra
+// entries are reported (relative to the roots and with '/' as the separator) together with a diff of each file that
+// has different content.
+func CheckDirsEqual(expectedDir, gotDir string, t testing.TB) {
+	t.Helper()
+	problems, err := fsDiff(os.DirFS(expectedDir), os.DirFS(gotDir))
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if len(problems) > 0 {
+		t.Fatalf("Expected directory %q to equal %q, but:\n%s", gotDir, expectedDir, strings.Join(problems, "\n"))
+	}
+}
+
+// CheckFSEqual checks that the two file systems have the same structure, with the same names of files and
+// directories, and that files with the same path have the same content. This makes it possible to compare for
+// example an embed.FS with output written to disk (using os.DirFS). On failure all missing and extra entries are
+// reported together with a diff of each file that has different content.
+func CheckFSEqual(expected, got fs.FS, t testing.TB) {
+	t.Helper()
+	problems, err := fsDiff(expected, got)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if len(problems) > 0 {
+		t.Fatalf("Expected file systems to be equal, but:\n%s", strings.Join(problems, "\n"))
+	}
+}
+
+// fsDiff returns a description of each difference between the two file systems in path order
+func fsDiff(efs, gfs fs.FS) ([]string, error) {
+	expected, err := fsEntries(efs)
+	if err != nil {
+		return nil, err
+	}
+	got, err := fsEntries(gfs)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	for _, name := range sortedKeys(expected) {
+		e := expected[name]
+		g, ok := got[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("missing %s %s", fileKind(e), name))
+		case e.IsDir() != g.IsDir():
+			problems = append(problems, fmt.Sprintf("expected %s to be a %s, got a %s", name, fileKind(e), fileKind(g)))
+		case !e.IsDir():
+			ec, err := fs.ReadFile(efs, name)
+			if err != nil {
+				return nil, err
+			}
+			gc, err := fs.ReadFile(gfs, name)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(ec, gc) {
+				problems = append(problems, fmt.Sprintf("content of %s differs:\n%s", name, contentDiff(ec, gc)))
+			}
+		}
+	}
+	for _, name := range sortedKeys(got) {
+		if _, ok := expected[name]; !ok {
+			problems = append(problems, fmt.Sprintf("extra %s %s", fileKind(got[name]), name))
+		}
+	}
+	return problems, nil
+}
+
+// fsEntries returns the entries of the file system keyed by their path, excluding the root
+func fsEntries(fsys fs.FS) (map[string]fs.DirEntry, error) {
+	entries := map[string]fs.DirEntry{}
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != "." {
+			entries[path] = d
+		}
+		return nil
+	})
+	return entries, err
+}
+
+func sortedKeys(m map[string]fs.DirEntry) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fileKind(d fs.DirEntry) string {
+	if d.IsDir() {
+		return "directory"
+	}
+	return "file"
+}
+
+// contentDiff returns a description of the difference between the expected and got file content. Text is shown as
+// a unified diff, and binary content as a hex dump of both at the first difference.
+func contentDiff(expected, got []byte) string {
+	if isText(expected) && isText(got) {
+		return unifiedDiff(strings.Split(string(expected), "\n"), strings.Split(string(got), "\n"))
+	}
+	offset := 0
+	for offset < len(expected) && offset < len(got) && expected[offset] == got[offset] {
+		offset++
+	}
+	start := hexWindowStart(int64(offset))
+	return hexDiff(window(expected, start), window(got, start), int64(offset))
+}
+
+// firstHunk returns the unified diff with all hunks but the first replaced by a note. Other text, such as a hex
+// dump, is returned as is.
+func firstHunk(diff string) string {
+	lines := strings.Split(diff, "\n")
+	hunks := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@ ") {
+			hunks++
+			if hunks == 2 {
+				return strings.Join(lines[:i], "\n") + "\n... (more differences follow)"
+			}
+		}
+	}
+	return diff
+}
+
+// hexWindowRows is the number of 16 byte rows shown by hexDiff
+const hexWindowRows = 4
+
+// hexWindowStart returns the offset of the window shown by hexDiff for a difference at the given offset. The
+// window starts one row before the row of the difference.
+func hexWindowStart(offset int64) int64 {
+	start := offset&^15 - 16
+	if start < 0 {
+		start = 0
+	}
+	return start
+}
+
+func window(data []byte, start int64) []byte {
+	if start >= int64(len(data)) {
+		return nil
+	}
+	end := start + hexWindowRows*16
+	if end > int64(len(data)) {
+		end = int64(len(data))
+	}
+	return data[start:end]
+}
+
+// readWindow reads the window shown by hexDiff for a difference at the given offset from the file
+func readWindow(f *os.File, offset int64) []byte {
+	buf := make([]byte, hexWindowRows*16)
+	n, _ := f.ReadAt(buf, hexWindowStart(offset))
+	return buf[:n]
+}
+
+// hexDiff returns hex dumps of the expected and got windows around the first difference at the given offset
+func hexDiff(expected, got []byte, offset int64) string {
+	start := hexWindowStart(offset)
+	return fmt.Sprintf("first difference at offset %d (0x%x)\nexpected:\n%sgot:\n%s", offset, offset,
+		hexDump(expected, start), hexDump(got, start))
+}
+
+// hexDump formats the data in rows of 16 bytes with the offset (starting at base), the bytes in hex, and the
+// printable ASCII characters
+func hexDump(data []byte, base int64) string {
+	if len(data) == 0 {
+		return "  (end of file)\n"
+	}
+	var sb strings.Builder
+	for i := 0; i < len(data); i += 16 {
+		row := data[i:]
+		if len(row) > 16 {
+			row = row[:16]
+		}
+		hex := make([]string, 16)
+		ascii := make([]byte, len(row))
+		for j := range hex {
+			if j < len(row) {
+				hex[j] = fmt.Sprintf("%02x", row[j])
+				if row[j] >= 0x20 && row[j] < 0x7f {
+					ascii[j] = row[j]
+				} else {
+					ascii[j] = '.'
+				}
+			} else {
+				hex[j] = "  "
+			}
+		}
+		fmt.Fprintf(&sb, "  %08x  %s  |%s|\n", base+int64(i), strings.Join(hex, " "), ascii)
+	}
+	return sb.String()
+}
+
+// firstDiffOffset reads the two readers and returns the offset of the first byte that differs (or the length of
+// the shorter if one is a prefix of the other), and true if there is no difference
+func firstDiffOffset(r1, r2 io.Reader) (int64, bool, error) {
+	b1 := make([]byte, chunkSize)
+	b2 := make([]byte, chunkSize)
+	var offset int64
+	for {
+		n1, err1 := io.ReadFull(r1, b1)
+		n2, err2 := io.ReadFull(r2, b2)
+		for _, err := range []error{err1, err2} {
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return 0, false, err
+			}
+		}
+		n := n1
+		if n2 < n {
+			n = n2
+		}
+		for i := 0; i < n; i++ {
+			if b1[i] != b2[i] {
+				return offset + int64(i), false, nil
+			}
+		}
+		if n1 != n2 {
+			return offset + int64(n), false, nil
+		}
+		if n1 < chunkSize {
+			return 0, true, nil
+		}
+		offset += int64(n1)
+	}
+}
+
+// isText returns true if the data is valid UTF-8 without NUL bytes
+func isText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
+
+// CheckFileMode checks that the permission bits of the given file (or directory) are equal to the given perm, i.e.
+// 0644. Note that only the write bit of the owner is significant on Windows.
+func CheckFileMode(filename string, perm os.FileMode, t testing.TB) {
+	t.Helper()
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got := info.Mode().Perm(); got != perm.Perm() {
+		t.Fatalf("Expected file %s to have mode %v, got %v", filename, perm.Perm(), got)
+	}
+}
+
+// CheckFileSize checks that the given file has the given size in bytes
+func CheckFileSize(filename string, size int64, t testing.TB) {
+	t.Helper()
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if info.Size() != size {
+		t.Fatalf("Expected file %s to have size %d, got %d", filename, size, info.Size())
+	}
+}
+
+// CheckFileNewerThan checks that the modification time of the given file is after the given time. Note that the
+// resolution of modification times depends on the file system.
+func CheckFileNewerThan(filename string, tm time.Time, t testing.TB) {
+	t.Helper()
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if !info.ModTime().After(tm) {
+		t.Fatalf("Expected file %s to be modified after %v, got %v", filename, tm, info.ModTime())
+	}
+}
+
+// CheckSymlinkTo checks that the given link is a symbolic link with the given target. The target is compared as
+// written in the link (i.e. as returned by os.Readlink) without being resolved.
+func CheckSymlinkTo(link, target string, t testing.TB) {
+	t.Helper()
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Expected %s to be a symbolic link, got mode %v", link, info.Mode())
+		return
+	}
+	got, err := os.Readlink(link)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got != target {
+		t.Fatalf("Expected symbolic link %s to have target %q, got %q", link, target, got)
+	}
+}
+
+// CheckNotSymlink checks that the given file exists and is not a symbolic link
+func CheckNotSymlink(filename string, t testing.TB) {
+	t.Helper()
+	info, err := os.Lstat(filename)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, _ := os.Readlink(filename)
+		t.Fatalf("Expected %s to not be a symbolic link, got a link to %q", filename, target)
+	}
+}
+
+// CheckFileSHA256 checks that the SHA-256 digest of the content of the given file is equal to the expected digest
+// given in hex. The file is streamed, which makes this suitable for verifying large artifacts.
+func CheckFileSHA256(filename, expectedHexDigest string, t testing.TB) {
+	t.Helper()
+	CheckFileHash(filename, sha256.New, expectedHexDigest, t)
+}
+
+// CheckFileHash checks that the digest of the content of the given file, computed with a hash.Hash returned by
+// newHash (i.e. sha1.New or md5.New), is equal to the expected digest given in hex (case does not matter). The
+// file is streamed and only the digests are reported on failure.
+func CheckFileHash(filename string, newHash func() hash.Hash, expectedHexDigest string, t testing.TB) {
+	t.Helper()
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+		return
+	}
+	defer f.Close()
+	h := newHash()
+	if _, err = io.Copy(h, f); err != nil {
+		t.Fatal(err)
+		return
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(got, expectedHexDigest) {
+		t.Fatalf("Expected file %s to have digest %s, got %s", filename, strings.ToLower(expectedHexDigest), got)
+	}
+}
+
+// CheckReadersEqual checks that the two readers produce the same content. The readers are compared chunk by chunk,
+// so large streams are never read into memory in full. On failure the offset of the first difference is reported
+// together with hex dumps of both streams around it.
+func CheckReadersEqual(expected, got io.Reader, t testing.TB) {
+	et := &tailBuffer{max: 2 * chunkSize}
+	gt := &tailBuffer{max: 2 * chunkSize}
+	er := io.TeeReader(expected, et)
+	gr := io.TeeReader(got, gt)
+	offset, equal, err := firstDiffOffset(er, gr)
+	if err != nil {
+		t.Helper()
+		t.Fatal(err)
+		return
+	}
+	if equal {
+		return
+	}
+	// read the rest of the window shown by hexDiff in case the difference is at the end of a chunk
+	rest := make([]byte, hexWindowRows*16)
+	_, _ = io.ReadFull(er, rest)
+	_, _ = io.ReadFull(gr, rest)
+	t.Helper()
+	t.Fatalf("Expected readers to have equal content, but:\n%s", hexDiff(et.window(offset), gt.window(offset), offset))
+}
+
+// tailBuffer is an io.Writer that keeps the last max bytes written to it
+type tailBuffer struct {
+	data []byte
+	// start is the offset of the first byte in data
+	start int64
+	max   int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	if drop := len(b.data) - b.max; drop > 0 {
+		b.data = append(b.data[:0:0], b.data[drop:]...)
+		b.start += int64(drop)
+	}
+	return len(p), nil
+}
+
+// window returns the window shown by hexDiff for a difference at the given offset
+func (b *tailBuffer) window(offset int64) []byte {
+	start := hexWindowStart(offset) - b.start
+	if start < 0 {
+		start = 0
+	}
+	return window(b.data, start)
+}
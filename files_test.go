@@ -0,0 +1,292 @@
+package testutils
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+	"time"
+)
+
+func writeTestFiles(root string, names ...string) {
+	for _, n := range names {
+		p := filepath.Join(root, filepath.FromSlash(n))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			panic(err)
+		}
+		if err := os.WriteFile(p, []byte(n), 0o644); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func TestCheckGlobMatches(t *testing.T) {
+	root := t.TempDir()
+	writeTestFiles(root, "a.go", "b.go", "c.txt", "sub/d.go")
+
+	CheckGlobMatches(root, "*.go", []string{"b.go", "a.go"}, t)
+	CheckGlobMatches(root, "*/*.go", []string{"sub/d.go"}, t)
+	CheckGlobMatches(root, "*.md", nil, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGlobMatches(root, "*.go", []string{"a.go"}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGlobMatches(root, "*.go", []string{"a.go", "b.go", "x.go"}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGlobMatches(root, "[", nil, ft)
+	})
+}
+
+func TestCheckDirsEqual(t *testing.T) {
+	expected := t.TempDir()
+	writeTestFiles(expected, "a.txt", "sub/b.txt", "sub/deeper/c.txt")
+	got := t.TempDir()
+	writeTestFiles(got, "a.txt", "sub/b.txt", "sub/deeper/c.txt")
+	CheckDirsEqual(expected, got, t)
+
+	writeTestFiles(got, "extra.txt")
+	if err := os.WriteFile(filepath.Join(got, "a.txt"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(got, "sub", "deeper")); err != nil {
+		t.Fatal(err)
+	}
+	m := &messageTB{}
+	CheckDirsEqual(expected, got, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckEqual(`Expected directory "`+got+`" to equal "`+expected+`", but:
+content of a.txt differs:
+--- expected
++++ got
+@@ -1 +1 @@
+-a.txt
++changed
+missing directory sub/deeper
+missing file sub/deeper/c.txt
+extra file extra.txt`, m.messages[0], t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckDirsEqual(expected, filepath.Join(got, "missing"), ft)
+	})
+}
+
+func TestCheckFSEqual(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(dir, "a.txt", "sub/b.txt")
+	expected := fstest.MapFS{
+		"a.txt":     {Data: []byte("a.txt")},
+		"sub/b.txt": {Data: []byte("sub/b.txt")},
+	}
+	CheckFSEqual(expected, os.DirFS(dir), t)
+
+	m := &messageTB{}
+	CheckFSEqual(fstest.MapFS{"a.txt": {Data: []byte("a.txt")}, "c.txt": {Data: []byte("c")}}, os.DirFS(dir), m)
+	CheckEqual([]string{"Expected file systems to be equal, but:\nmissing file c.txt\nextra directory sub\nextra file sub/b.txt"}, m.messages, t)
+}
+
+func Test_contentDiff(t *testing.T) {
+	CheckMatches(`^first difference at offset 0 `, contentDiff([]byte{0, 1}, []byte{1}), t)
+	// text is shown with all hunks, only CheckFilesEqual shows the first hunk
+	diff := contentDiff([]byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10"), []byte("x\n2\n3\n4\n5\n6\n7\n8\n9\ny"))
+	CheckEqual(2, strings.Count(diff, "\n@@ "), t)
+}
+
+func TestCheckFilesEqual(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, content []byte) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	a := write("a.txt", []byte("one\ntwo\nthree\n"))
+	CheckFilesEqual(a, write("a2.txt", []byte("one\ntwo\nthree\n")), t)
+
+	// the second file was not read before (the first file was opened twice)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFilesEqual(a, write("b.txt", []byte("one\nTWO\nthree\n")), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFilesEqual(a, dir, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFilesEqual(a, filepath.Join(dir, "missing"), ft)
+	})
+
+	m := &messageTB{}
+	b := write("b.txt", []byte("one\nTWO\nthree\n"))
+	CheckFilesEqual(a, b, m)
+	CheckEqual(fmt.Sprintf("content of file %q (14 bytes) and %q (14 bytes) differ:\n", a, b)+
+		"--- expected\n+++ got\n@@ -1,4 +1,4 @@\n one\n-two\n+TWO\n three\n ", m.messages[0], t)
+
+	// larger than maxTextDiffSize to read the dump from the files
+	bin1 := make([]byte, maxTextDiffSize+100)
+	bin2 := make([]byte, maxTextDiffSize+100)
+	bin2[maxTextDiffSize+20] = 1
+	m = &messageTB{}
+	CheckFilesEqual(write("a.bin", bin1), write("b.bin", bin2), m)
+	CheckMatches(`(?s)first difference at offset 1048596 \(0x100014\)\nexpected:\n  00100000  00 .*got:\n  00100000  00 .*\n  00100010  00 00 00 00 01`, m.messages[0], t)
+}
+
+func Test_firstDiffOffset(t *testing.T) {
+	for _, c := range []struct {
+		a, b   string
+		offset int64
+		equal  bool
+	}{
+		{"", "", 0, true},
+		{"abc", "abc", 0, true},
+		{"abc", "abd", 2, false},
+		{"abc", "ab", 2, false},
+		{"", "a", 0, false},
+	} {
+		offset, equal, err := firstDiffOffset(strings.NewReader(c.a), strings.NewReader(c.b))
+		CheckNotError(err, t)
+		CheckEqual(c.offset, offset, t)
+		CheckEqual(c.equal, equal, t)
+	}
+}
+
+func Test_contentDiff_binary(t *testing.T) {
+	expected := []byte("0123456789abcdef0123456789abcdef\x00x")
+	got := []byte("0123456789abcdef0123456789abcdef\x00y!")
+	CheckEqual("first difference at offset 33 (0x21)\n"+
+		"expected:\n"+
+		"  00000010  30 31 32 33 34 35 36 37 38 39 61 62 63 64 65 66  |0123456789abcdef|\n"+
+		"  00000020  00 78                                            |.x|\n"+
+		"got:\n"+
+		"  00000010  30 31 32 33 34 35 36 37 38 39 61 62 63 64 65 66  |0123456789abcdef|\n"+
+		"  00000020  00 79 21                                         |.y!|\n", contentDiff(expected, got), t)
+	CheckMatches(`got:\n  \(end of file\)\n$`, contentDiff([]byte{0, 1}, []byte{}), t)
+}
+
+func Test_firstHunk(t *testing.T) {
+	e := strings.Split("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12", "\n")
+	g := strings.Split("x\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ny", "\n")
+	CheckEqual("--- expected\n+++ got\n@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n... (more differences follow)", firstHunk(unifiedDiff(e, g)), t)
+}
+
+func TestCheckFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not supported on Windows")
+	}
+	dir := t.TempDir()
+	writeTestFiles(dir, "a")
+	name := filepath.Join(dir, "a")
+	if err := os.Chmod(name, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	CheckFileMode(name, 0o640, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileMode(name, 0o644, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileMode(filepath.Join(dir, "missing"), 0o644, ft)
+	})
+}
+
+func TestCheckFileSize(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(dir, "abc")
+	CheckFileSize(filepath.Join(dir, "abc"), 3, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileSize(filepath.Join(dir, "abc"), 4, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileSize(filepath.Join(dir, "missing"), 0, ft)
+	})
+}
+
+func TestCheckFileNewerThan(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(dir, "a")
+	name := filepath.Join(dir, "a")
+	mtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(name, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	CheckFileNewerThan(name, mtime.Add(-time.Second), t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileNewerThan(name, mtime, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileNewerThan(filepath.Join(dir, "missing"), mtime, ft)
+	})
+}
+
+func TestCheckSymlinkTo(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(dir, "a")
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink("a", link); err != nil {
+		t.Skip("symbolic links are not supported:", err)
+	}
+	CheckSymlinkTo(link, "a", t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckSymlinkTo(link, filepath.Join(dir, "a"), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckSymlinkTo(filepath.Join(dir, "a"), "a", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckSymlinkTo(filepath.Join(dir, "missing"), "a", ft)
+	})
+
+	CheckNotSymlink(filepath.Join(dir, "a"), t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckNotSymlink(link, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckNotSymlink(filepath.Join(dir, "missing"), ft)
+	})
+}
+
+func TestCheckFileSHA256(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(dir, "a.txt")
+	name := filepath.Join(dir, "a.txt")
+	CheckFileSHA256(name, "18b7cb099a9ea3f50ba899b5ba81e0d377a5f3b16f8f6eeb8b3e58cd4692b993", t)
+	CheckFileHash(name, md5.New, "A5E54D1FD7BB69A228EF0DCD2431367E", t)
+
+	m := &messageTB{}
+	CheckFileSHA256(name, "00", m)
+	CheckEqual([]string{"Expected file " + name + " to have digest 00, got 18b7cb099a9ea3f50ba899b5ba81e0d377a5f3b16f8f6eeb8b3e58cd4692b993"}, m.messages, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckFileSHA256(filepath.Join(dir, "missing"), "00", ft)
+	})
+}
+
+func TestCheckReadersEqual(t *testing.T) {
+	data := make([]byte, 3*chunkSize)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	CheckReadersEqual(bytes.NewReader(data), bytes.NewReader(data), t)
+
+	changed := append([]byte{}, data...)
+	offset := 2*chunkSize - 2
+	changed[offset] = 'x'
+	m := &messageTB{}
+	CheckReadersEqual(bytes.NewReader(data), bytes.NewReader(changed), m)
+	CheckReadersEqual(strings.NewReader("abc"), strings.NewReader("ab"), m)
+	CheckEqual(2, len(m.messages), t)
+	CheckMatches(`^Expected readers to have equal content, but:\nfirst difference at offset 131070 \(0x1fffe\)\n`, m.messages[0], t)
+	CheckMatches(`(?m)^  0001fff0  f0 f1 f2 f3 f4 f5 f6 f7 f8 f9 fa fb fc fd 78 ff  \|\.{14}x\.\|$`, m.messages[0], t)
+	CheckMatches(`(?m)^  00020000  00 01 02`, m.messages[0], t)
+	CheckEqual("Expected readers to have equal content, but:\nfirst difference at offset 2 (0x2)\n"+
+		"expected:\n  00000000  61 62 63                                         |abc|\ngot:\n"+
+		"  00000000  61 62                                            |ab|\n", m.messages[1], t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReadersEqual(iotest.ErrReader(io.ErrClosedPipe), strings.NewReader(""), ft)
+	})
+}
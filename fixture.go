@@ -0,0 +1,121 @@
+package testutils
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// FixtureFile is the content and mode of a file created by TempDirWith
+type FixtureFile struct {
+	Content []byte
+	// Mode is the permission bits of the file, 0644 if not set
+	Mode os.FileMode
+}
+
+// TempDirWith creates a new temporary directory (see testing.TB.TempDir, the directory is removed when the test
+// finishes) with the given files, and returns its path. The files are keyed by their path relative to the root
+// using '/' as the separator, and missing parent directories are created. The value of a file is its content
+// given as a string or a []byte, or a FixtureFile to also set its mode. A path that ends with '/' creates an
+// (empty) directory and its value is ignored.
+func TempDirWith(files map[string]interface{}, t testing.TB) string {
+	t.Helper()
+	root := t.TempDir()
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatal(err)
+				return root
+			}
+			continue
+		}
+		var f FixtureFile
+		switch v := files[name].(type) {
+		case string:
+			f.Content = []byte(v)
+		case []byte:
+			f.Content = v
+		case FixtureFile:
+			f = v
+		default:
+			t.Fatalf("TempDirWith: content of %s must be a string, []byte, or FixtureFile, got %T", name, v)
+			return root
+		}
+		if f.Mode == 0 {
+			f.Mode = 0o644
+		}
+		if err := writeFixtureFile(path, f); err != nil {
+			t.Fatal(err)
+			return root
+		}
+	}
+	return root
+}
+
+func writeFixtureFile(path string, f FixtureFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, f.Content, f.Mode); err != nil {
+		return err
+	}
+	// the mode given to WriteFile is modified by umask
+	if err := os.Chmod(path, f.Mode); err != nil {
+		return fmt.Errorf("unable to set mode of %s: %w", path, err)
+	}
+	return nil
+}
+
+// CopyFixture recursively copies the directory srcDir, typically a directory under testdata, into a new temporary
+// directory (see testing.TB.TempDir) and returns its path. This lets a test modify fixture files without changing
+// the shared originals. The permission bits of files and directories are preserved, and symbolic links are copied
+// as links.
+func CopyFixture(srcDir string, t testing.TB) string {
+	t.Helper()
+	root := t.TempDir()
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(root, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return writeFixtureFile(target, FixtureFile{Content: content, Mode: info.Mode().Perm()})
+		}
+		return fmt.Errorf("CopyFixture: unable to copy %s of type %s", path, d.Type())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
@@ -0,0 +1,54 @@
+package testutils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestTempDirWith(t *testing.T) {
+	root := TempDirWith(map[string]interface{}{
+		"a.txt":          "a",
+		"sub/b.bin":      []byte{0, 1},
+		"sub/deep/x.sh":  FixtureFile{Content: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"empty/":         nil,
+		"empty/also/new": "",
+	}, t)
+	CheckFileSize(filepath.Join(root, "a.txt"), 1, t)
+	CheckFileSize(filepath.Join(root, "sub", "b.bin"), 2, t)
+	CheckFileSize(filepath.Join(root, "sub", "deep", "x.sh"), 10, t)
+	CheckDirExists(filepath.Join(root, "empty"), t)
+	if runtime.GOOS != "windows" {
+		CheckFileMode(filepath.Join(root, "a.txt"), 0o644, t)
+		CheckFileMode(filepath.Join(root, "sub", "deep", "x.sh"), 0o755, t)
+	}
+
+	r := NewRecordingTB(t)
+	r.Run(func(tb testing.TB) { TempDirWith(map[string]interface{}{"a": 1}, tb) })
+	CheckEqual([]string{"TempDirWith: content of a must be a string, []byte, or FixtureFile, got int"}, r.Failures(), t)
+}
+
+func TestCopyFixture(t *testing.T) {
+	src := TempDirWith(map[string]interface{}{
+		"a.txt":         "a",
+		"sub/deep/x.sh": FixtureFile{Content: []byte("#!/bin/sh\n"), Mode: 0o755},
+		"empty/":        nil,
+	}, t)
+	dir := CopyFixture(src, t)
+	CheckNotEqual(src, dir, t)
+	CheckDirsEqual(src, dir, t)
+	if runtime.GOOS != "windows" {
+		CheckFileMode(filepath.Join(dir, "sub", "deep", "x.sh"), 0o755, t)
+	}
+
+	// modifying the copy leaves the original unchanged
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	CheckFileSize(filepath.Join(src, "a.txt"), 1, t)
+
+	r := NewRecordingTB(t)
+	r.Run(func(tb testing.TB) { CopyFixture(filepath.Join(src, "missing"), tb) })
+	CheckEqual(1, len(r.Failures()), t)
+}
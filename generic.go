@@ -0,0 +1,175 @@
+package testutils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// The checks in this file are typed counterparts of the interface{} based checks. Since the expected and
+// produced values must be of the same type a type mismatch is caught at compile time, and no reflection
+// is used when comparing values.
+//
+// Note that numeric values of different types are never considered equal since they cannot be passed to
+// the same check.
+
+// CheckEqualT checks if two values of the same comparable type are equal and calls t.Fatalf if not
+func CheckEqualT[T comparable](expected, got T, t testing.TB) {
+	if expected != got {
+		t.Helper()
+		unequalValues(expected, got, t)
+	}
+}
+
+// CheckNotEqualT checks if two values of the same comparable type are not equal and calls t.Fatalf if they are
+func CheckNotEqualT[T comparable](expected, got T, t testing.TB) {
+	if expected == got {
+		t.Helper()
+		equalValues(expected, got, t)
+	}
+}
+
+// CheckSliceEqualT checks if two slices have the same length and equal elements in the same order. On failure
+// the index and values of the first unequal elements are reported, followed by the missing or extra elements at
+// the end of got if the lengths differ, instead of both slices in full.
+func CheckSliceEqualT[T comparable](expected, got []T, t testing.TB) {
+	if !sliceEqualT(expected, got) {
+		t.Helper()
+		t.Fatalf("Expected equal slices of %T, but:\n%s", expected, sliceDiffT(expected, got))
+	}
+}
+
+// CheckEqualElementsT checks if two slices contains the exact same set of elements irrespective of order.
+// Each element in expected must be matched by exactly one element in got.
+func CheckEqualElementsT[T comparable](expected, got []T, t testing.TB) {
+	if len(expected) != len(got) {
+		t.Helper()
+		t.Fatalf("Elements of slice %v and %v differ", expected, got)
+		return
+	}
+	counts := make(map[T]int, len(expected))
+	for _, e := range expected {
+		counts[e]++
+	}
+	for _, g := range got {
+		if counts[g] == 0 {
+			t.Helper()
+			t.Fatalf("Elements of slice %v and %v differ", expected, got)
+			return
+		}
+		counts[g]--
+	}
+}
+
+// CheckMapEqualT checks if two maps have the same set of keys and equal values for each key. On failure the
+// missing, extra, and changed keys are reported in key order.
+func CheckMapEqualT[K, V comparable](expected, got map[K]V, t testing.TB) {
+	if !mapEqualT(expected, got) {
+		t.Helper()
+		t.Fatalf("Expected equal maps of %T, but:\n%s", expected, mapDiffT(expected, got, func(a, b V) bool { return a == b }))
+	}
+}
+
+// CheckMapEqualFuncT checks if two maps have the same set of keys and values for each key that are equal according
+// to the given equal function. This is useful for maps with values that are not comparable, or that should be
+// compared in a special way. On failure the missing, extra, and changed keys are reported in key order.
+func CheckMapEqualFuncT[K comparable, V any](expected, got map[K]V, equal func(a, b V) bool, t testing.TB) {
+	if diff := mapDiffT(expected, got, equal); diff != "" {
+		t.Helper()
+		t.Fatalf("Expected equal maps of %T, but:\n%s", expected, diff)
+	}
+}
+
+func sliceEqualT[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func mapEqualT[K, V comparable](a, b map[K]V) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, va := range a {
+		if vb, ok := b[k]; !ok || va != vb {
+			return false
+		}
+	}
+	return true
+}
+
+// mapDiffT returns a description of the missing, extra, and changed keys of the two maps, one per line in the
+// order of the formatted keys, or an empty string if the maps are equal
+func mapDiffT[K comparable, V any](expected, got map[K]V, equal func(a, b V) bool) string {
+	keys := make([]K, 0, len(expected)+len(got))
+	for k := range expected {
+		keys = append(keys, k)
+	}
+	for k := range got {
+		if _, ok := expected[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+
+	var lines []string
+	for _, k := range keys {
+		e, eok := expected[k]
+		g, gok := got[k]
+		switch {
+		case !gok:
+			lines = append(lines, fmt.Sprintf("  missing key %v: expected %v", k, e))
+		case !eok:
+			lines = append(lines, fmt.Sprintf("  extra key %v: got %v", k, g))
+		case !equal(e, g):
+			lines = append(lines, fmt.Sprintf("  changed key %v: expected %v, got %v", k, e, g))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sliceDiffLimit is the max number of unequal elements, and of missing or extra elements, reported by CheckSliceEqualT
+const sliceDiffLimit = 5
+
+// sliceDiffT returns a description of the unequal elements of the two slices, one per line
+func sliceDiffT[T comparable](expected, got []T) string {
+	n := len(expected)
+	if len(got) < n {
+		n = len(got)
+	}
+	var lines []string
+	unequal := 0
+	for i := 0; i < n; i++ {
+		if expected[i] != got[i] {
+			if unequal < sliceDiffLimit {
+				lines = append(lines, fmt.Sprintf("  [%d]: expected %v, got %v", i, expected[i], got[i]))
+			}
+			unequal++
+		}
+	}
+	if unequal > sliceDiffLimit {
+		lines = append(lines, fmt.Sprintf("  ... and %d more unequal elements", unequal-sliceDiffLimit))
+	}
+	switch {
+	case len(expected) > n:
+		lines = append(lines, fmt.Sprintf("  missing %d elements at [%d:]: %s", len(expected)-n, n, tailText(expected[n:])))
+	case len(got) > n:
+		lines = append(lines, fmt.Sprintf("  extra %d elements at [%d:]: %s", len(got)-n, n, tailText(got[n:])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tailText formats the elements, cut short after sliceDiffLimit elements
+func tailText[T any](tail []T) string {
+	if len(tail) > sliceDiffLimit {
+		return strings.TrimSuffix(fmt.Sprint(tail[:sliceDiffLimit]), "]") + " ...]"
+	}
+	return fmt.Sprint(tail)
+}
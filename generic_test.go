@@ -0,0 +1,75 @@
+package testutils
+
+import "testing"
+
+func TestCheckEqualT(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckEqualT("a", "a", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckEqualT(1, 2, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckNotEqualT(1, 1, ft)
+	})
+}
+
+func TestCheckSliceEqualT(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckSliceEqualT([]string{"a", "b"}, []string{"a", "b"}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckSliceEqualT([]string{"a", "b"}, []string{"b", "a"}, ft)
+	})
+
+	m := &messageTB{}
+	CheckSliceEqualT([]int{1, 2, 3, 4, 5, 6, 7, 8}, []int{1, 0, 3, 0, 0, 0, 0, 0, 9, 10}, m)
+	CheckSliceEqualT([]string{"a", "b", "c", "d", "e", "f", "g", "h"}, []string{"a", "b"}, m)
+	CheckEqual([]string{
+		"Expected equal slices of []int, but:\n" +
+			"  [1]: expected 2, got 0\n" +
+			"  [3]: expected 4, got 0\n" +
+			"  [4]: expected 5, got 0\n" +
+			"  [5]: expected 6, got 0\n" +
+			"  [6]: expected 7, got 0\n" +
+			"  ... and 1 more unequal elements\n" +
+			"  extra 2 elements at [8:]: [9 10]",
+		"Expected equal slices of []string, but:\n" +
+			"  missing 6 elements at [2:]: [c d e f g ...]",
+	}, m.messages, t)
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckEqualElementsT([]string{"a", "b", "a"}, []string{"b", "a", "a"}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckEqualElementsT([]string{"a", "b", "a"}, []string{"b", "a", "b"}, ft)
+	})
+}
+
+func TestCheckMapEqualT(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckMapEqualT(map[string]int{"a": 1, "b": 2}, map[string]int{"b": 2, "a": 1}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckMapEqualT(map[string]int{"a": 1}, map[string]int{"a": 2}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckMapEqualT(map[string]int{"a": 1}, map[string]int{"b": 1}, ft)
+	})
+
+	m := &messageTB{}
+	CheckMapEqualT(map[string]int{"a": 1, "b": 2, "c": 3}, map[string]int{"b": 2, "c": 4, "d": 5}, m)
+	CheckEqual([]string{"Expected equal maps of map[string]int, but:\n" +
+		"  missing key a: expected 1\n" +
+		"  changed key c: expected 3, got 4\n" +
+		"  extra key d: got 5"}, m.messages, t)
+}
+
+func TestCheckMapEqualFuncT(t *testing.T) {
+	CheckMapEqualFuncT(map[int][]string{1: {"a"}}, map[int][]string{1: {"a"}}, sliceEqualT[string], t)
+
+	m := &messageTB{}
+	CheckMapEqualFuncT(map[int][]string{1: {"a"}, 2: nil}, map[int][]string{1: {"b"}}, sliceEqualT[string], m)
+	CheckEqual([]string{"Expected equal maps of map[int][]string, but:\n" +
+		"  changed key 1: expected [a], got [b]\n" +
+		"  missing key 2: expected []"}, m.messages, t)
+}
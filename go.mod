@@ -1,5 +1,10 @@
 module github.com/hlindberg/testutils
 
-go 1.15
+go 1.20
 
-require github.com/sergi/go-diff v1.2.0
+require (
+	github.com/sergi/go-diff v1.2.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/stretchr/testify v1.8.4 // indirect
@@ -0,0 +1,104 @@
+package testutils
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the name of an environment variable that, when set to a non empty value, has the same effect
+// as the -update flag.
+const UpdateGoldenEnv = "TESTUTILS_UPDATE_GOLDEN"
+
+// UpdateGolden returns true if golden files should be updated instead of compared, which is the case when the
+// environment variable named by UpdateGoldenEnv is set, or when tests are run with -update=true. This package does
+// not register an -update flag since that would clash with test packages that define their own, so to use -update
+// the test package must define it, for example as `var _ = flag.Bool("update", false, "update golden files")`.
+// Any boolean flag named "update" is honored.
+func UpdateGolden() bool {
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		if g, ok := f.Value.(flag.Getter); ok {
+			b, ok := g.Get().(bool)
+			return ok && b
+		}
+	}
+	return false
+}
+
+// CheckGolden compares the got text with the content of the given golden file (typically a file under testdata)
+// and fails with a unified diff if they differ. When UpdateGolden() returns true, the golden file (and its
+// directory) is instead created or overwritten with the got text. Registered redactions (see RegisterRedactions)
+// are applied to the got text first.
+//
+// A golden file can have platform specific variants for output that legitimately differs between platforms. For
+// the golden file `name.golden`, the first existing file of `name_GOOS_GOARCH.golden`, `name_GOOS.golden`,
+// `name_GOARCH.golden`, and `name.golden` is used (i.e. `name_windows.golden` on Windows). An existing variant is
+// also the file that is updated.
+func CheckGolden(filename, got string, t testing.TB) {
+	t.Helper()
+	got = redact(got)
+	filename = goldenVariant(filename)
+	if UpdateGolden() {
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+			return
+		}
+		if err := os.WriteFile(filename, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+			return
+		}
+		t.Logf("updated golden file %s", filename)
+		return
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unable to read golden file (run with -update to create it): %s", err.Error())
+		return
+	}
+	if expected := string(data); expected != got {
+		t.Fatalf("output does not match golden file %s (run with -update to update it) - see diff:\n%s",
+			filename, unifiedDiff(strings.Split(expected, "\n"), strings.Split(got, "\n")))
+	}
+}
+
+// goos and goarch are the platform used when selecting golden file variants. They are variables to allow them to
+// be overridden.
+var (
+	goos   = runtime.GOOS
+	goarch = runtime.GOARCH
+)
+
+// goldenVariant returns the name of the first existing platform specific variant of the golden file, or the
+// given name if there is no such variant
+func goldenVariant(filename string) string {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	for _, suffix := range variantSuffixes(goos, goarch) {
+		variant := base + suffix + ext
+		if _, err := os.Stat(variant); err == nil {
+			return variant
+		}
+	}
+	return filename
+}
+
+// variantSuffixes returns the suffixes of the platform specific variants of a golden file for the given platform, in
+// order of precedence
+func variantSuffixes(goos, goarch string) []string {
+	return []string{"_" + goos + "_" + goarch, "_" + goos, "_" + goarch}
+}
+
+// knownGOOS and knownGOARCH are the platforms (as listed by `go tool dist list`) recognized in the names of
+// platform specific variants of golden files made on other platforms
+var (
+	knownGOOS = []string{"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios", "js", "linux", "netbsd",
+		"openbsd", "plan9", "solaris", "wasip1", "windows"}
+	knownGOARCH = []string{"386", "amd64", "arm", "arm64", "loong64", "mips", "mips64", "mips64le", "mipsle", "ppc64",
+		"ppc64le", "riscv64", "s390x", "wasm"}
+)
@@ -0,0 +1,68 @@
+package testutils
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update is the -update flag of the tests of this package, which UpdateGolden finds by name
+var update = flag.Bool("update", false, "update golden files instead of comparing with them")
+
+func TestUpdateGolden_flag(t *testing.T) {
+	if UpdateGolden() {
+		t.Skip("already in update mode")
+	}
+	CheckNotError(flag.Set("update", "true"), t)
+	defer func() { CheckNotError(flag.Set("update", "false"), t) }()
+	CheckTrue(*update, t)
+	CheckTrue(UpdateGolden(), t)
+}
+
+func TestCheckGolden(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "sub", "out.golden")
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGolden(filename, "hello\n", ft)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		t.Setenv(UpdateGoldenEnv, "1")
+		CheckTrue(UpdateGolden(), t)
+		CheckGolden(filename, "hello\n", t)
+	})
+	data, err := os.ReadFile(filename)
+	CheckNotError(err, t)
+	CheckEqual("hello\n", string(data), t)
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckGolden(filename, "hello\n", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGolden(filename, "hello world\n", ft)
+	})
+}
+
+func Test_goldenVariant(t *testing.T) {
+	savedOS, savedArch := goos, goarch
+	defer func() { goos, goarch = savedOS, savedArch }()
+	goos, goarch = "windows", "arm64"
+
+	dir := t.TempDir()
+	name := filepath.Join(dir, "out.golden")
+	CheckEqual(name, goldenVariant(name), t)
+	for _, variant := range []string{"out_arm64.golden", "out_windows.golden", "out_windows_arm64.golden"} {
+		v := filepath.Join(dir, variant)
+		if err := os.WriteFile(v, []byte(variant), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		CheckEqual(v, goldenVariant(name), t)
+	}
+	if !UpdateGolden() {
+		CheckGolden(name, "out_windows_arm64.golden", t)
+	}
+
+	goos = "linux"
+	CheckEqual(filepath.Join(dir, "out_arm64.golden"), goldenVariant(name), t)
+}
@@ -0,0 +1,159 @@
+package testutils
+
+import (
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// CheckCompletesWithin calls fn in a new goroutine and checks that it returns within the given duration. This
+// detects hangs and deadlocks without waiting for the timeout of the test binary. On failure the stacks of all
+// goroutines are reported, and the goroutine running fn is abandoned (left running). A panic in fn is reported
+// as a failure, and so is a call to runtime.Goexit in fn (e.g. from t.FailNow) since fn then exits without
+// returning.
+func CheckCompletesWithin(d time.Duration, fn func(), t testing.TB) {
+	t.Helper()
+	CheckCompletesWithinWith(d, fn, ClockOptions{}, t)
+}
+
+// CheckCompletesWithinWith is CheckCompletesWithin with options
+func CheckCompletesWithinWith(d time.Duration, fn func(), opts ClockOptions, t testing.TB) {
+	done := make(chan interface{}, 1)
+	completed := false
+	go func() {
+		var recovered interface{}
+		defer func() { done <- recovered }()
+		defer func() { recovered = recover() }()
+		fn()
+		completed = true
+	}()
+	timer := opts.clock().NewTimer(d)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		switch {
+		case r != nil:
+			t.Helper()
+			t.Fatalf("Expected function to complete, but it panicked: %v", r)
+		case !completed:
+			t.Helper()
+			t.Fatalf("Expected function to complete, but it exited without returning")
+		}
+	case <-timer.C():
+		t.Helper()
+		t.Fatalf("Expected function to complete within %v, but it did not. Goroutines:\n%s", d, goroutineDump())
+	}
+}
+
+// goroutineDump returns the stacks of all goroutines as formatted by runtime.Stack
+func goroutineDump() string {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// leakWait is the max time VerifyNoGoroutineLeaks waits for new goroutines to finish
+var leakWait = time.Second
+
+// ignoredGoroutineFuncs are prefixes of the top level functions of goroutines started by the runtime and the
+// testing package, which are not reported as leaks
+var ignoredGoroutineFuncs = []string{
+	"testing.",
+	"runtime.",
+	"os/signal.",
+}
+
+// VerifyNoGoroutineLeaks records the goroutines that are running when it is called, and registers a cleanup
+// function that checks that no other goroutines are running when the test finishes. It is typically called first
+// in a test. Goroutines are given some time to finish before they are considered leaked, and goroutines of the
+// runtime and the testing package are ignored. On failure the stacks of the leaked goroutines are reported.
+//
+// Tests that use VerifyNoGoroutineLeaks should not run in parallel since their goroutines cannot be told apart.
+func VerifyNoGoroutineLeaks(t testing.TB) {
+	t.Helper()
+	before := goroutineStacks()
+	t.Cleanup(func() {
+		t.Helper()
+		checkNoGoroutineLeaks(before, leakWait, t)
+	})
+}
+
+func checkNoGoroutineLeaks(before map[string]string, wait time.Duration, t testing.TB) {
+	deadline := time.Now().Add(wait)
+	for {
+		var leaked []string
+		stacks := goroutineStacks()
+		for _, id := range sortedGoroutineIDs(stacks) {
+			if _, ok := before[id]; !ok && !ignoredGoroutine(stacks[id]) {
+				leaked = append(leaked, stacks[id])
+			}
+		}
+		if len(leaked) == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Helper()
+			t.Fatalf("Expected no leaked goroutines, found %d:\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// goroutineStacks returns the stack of each goroutine (except the calling goroutine) keyed by goroutine id
+func goroutineStacks() map[string]string {
+	stacks := map[string]string{}
+	for i, stack := range strings.Split(strings.TrimSpace(goroutineDump()), "\n\n") {
+		if i == 0 {
+			// the calling goroutine is always listed first
+			continue
+		}
+		fields := strings.Fields(stack)
+		if len(fields) > 1 && fields[0] == "goroutine" {
+			stacks[fields[1]] = stack
+		}
+	}
+	return stacks
+}
+
+// ignoredGoroutine returns true if the function the goroutine was started with (the last function listed in the
+// stack unless the stack is cut short) is an ignored function
+func ignoredGoroutine(stack string) bool {
+	lines := strings.Split(stack, "\n")
+	start := ""
+	for i := len(lines) - 1; i > 0; i-- {
+		line := lines[i]
+		// file positions are indented, and the creator and elided frames are not functions of the goroutine
+		if !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "created by ") && !strings.HasPrefix(line, "...") {
+			start = line
+			break
+		}
+	}
+	for _, prefix := range ignoredGoroutineFuncs {
+		if strings.HasPrefix(start, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedGoroutineIDs(stacks map[string]string) []string {
+	ids := make([]string, 0, len(stacks))
+	for id := range stacks {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+	return ids
+}
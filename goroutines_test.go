@@ -0,0 +1,67 @@
+package testutils
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCheckCompletesWithin(t *testing.T) {
+	CheckCompletesWithin(time.Second, func() {}, t)
+
+	block := make(chan struct{})
+	defer close(block)
+	m := &messageTB{}
+	CheckCompletesWithin(5*time.Millisecond, func() { <-block }, m)
+	CheckCompletesWithin(time.Second, func() { panic("boom") }, m)
+	CheckCompletesWithin(time.Second, runtime.Goexit, m)
+	CheckEqual(3, len(m.messages), t)
+	CheckMatches(`^Expected function to complete within 5ms, but it did not. Goroutines:\ngoroutine \d+ `, m.messages[0], t)
+	CheckMatches(`TestCheckCompletesWithin`, m.messages[0], t)
+	CheckEqual("Expected function to complete, but it panicked: boom", m.messages[1], t)
+	CheckEqual("Expected function to complete, but it exited without returning", m.messages[2], t)
+}
+
+func TestVerifyNoGoroutineLeaks(t *testing.T) {
+	VerifyNoGoroutineLeaks(t)
+	done := make(chan struct{})
+	go func() { <-done }()
+	// the goroutine finishes after the test function returns, but within the wait
+	time.AfterFunc(5*time.Millisecond, func() { close(done) })
+}
+
+func Test_checkNoGoroutineLeaks(t *testing.T) {
+	before := goroutineStacks()
+	block := make(chan struct{})
+	defer close(block)
+	go func() { <-block }()
+
+	m := &messageTB{}
+	checkNoGoroutineLeaks(before, 20*time.Millisecond, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected no leaked goroutines, found 1:\n\ngoroutine \d+ \[chan receive\]:\n`, m.messages[0], t)
+	CheckMatches(`Test_checkNoGoroutineLeaks\.func1`, m.messages[0], t)
+
+	checkNoGoroutineLeaks(goroutineStacks(), 0, t)
+}
+
+func Test_ignoredGoroutine(t *testing.T) {
+	CheckTrue(ignoredGoroutine("goroutine 1 [chan receive]:\ntesting.(*T).Run(0x1, {0x2, 0x3}, 0x4)\n\t/go/src/testing/testing.go:1750 +0x3ab\n"+
+		"testing.runTests.func1(0x5)\n\t/go/src/testing/testing.go:2161 +0x37\ntesting.tRunner(0x6, 0x7)\n\t/go/src/testing/testing.go:1689 +0xfb"), t)
+	CheckFalse(ignoredGoroutine("goroutine 9 [select]:\nexample.com/x.worker()\n\t/x/worker.go:10 +0x1\n"+
+		"created by example.com/x.Start in goroutine 8\n\t/x/worker.go:5 +0x2"), t)
+}
+
+func TestCheckCompletesWithinWith(t *testing.T) {
+	clock := NewFakeClock(clockStart)
+	advanceWhenWaiting(clock, 2, time.Minute)
+	CheckCompletesWithinWith(time.Hour, func() { clock.Sleep(time.Minute) }, ClockOptions{Clock: clock}, t)
+
+	block := make(chan struct{})
+	defer close(block)
+	m := &messageTB{}
+	advanceWhenWaiting(clock, 1, time.Hour)
+	CheckCompletesWithinWith(time.Hour, func() { <-block }, ClockOptions{Clock: clock}, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected function to complete within 1h0m0s, but it did not`, m.messages[0], t)
+}
@@ -0,0 +1,246 @@
+package testutils
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// CheckGofmtEqual checks that the expected and got Go source are equal after both have been formatted with gofmt.
+// This makes it possible to check the output of a code generator without being sensitive to its formatting. On
+// failure a diff of the formatted source is reported.
+func CheckGofmtEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	e, err := format.Source([]byte(expected))
+	if err != nil {
+		t.Fatalf("CheckGofmtEqual: expected is not valid Go source: %s", err.Error())
+		return
+	}
+	g, err := format.Source([]byte(got))
+	if err != nil {
+		t.Fatalf("Expected valid Go source, got error: %s", err.Error())
+		return
+	}
+	CheckTextEqual(string(e), string(g), t)
+}
+
+// CheckParsesAsGo checks that the given source is a syntactically valid Go source file
+func CheckParsesAsGo(src string, t testing.TB) {
+	if _, err := parseGoFile(src); err != nil {
+		t.Helper()
+		t.Fatalf("Expected valid Go source, got error: %s", err.Error())
+	}
+}
+
+// CheckGeneratedCompiles checks that the given Go source file compiles as a package of its own, by type checking
+// it with go/types. Imported packages are type checked from their source as found by the go command, which means
+// that they can be packages of the standard library, of the module of the test, or of its dependencies. On
+// failure all type errors are reported.
+func CheckGeneratedCompiles(src string, t testing.TB) {
+	t.Helper()
+	fs := token.NewFileSet()
+	f, err := parser.ParseFile(fs, "src.go", src, parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatalf("Expected valid Go source, got error: %s", err.Error())
+		return
+	}
+	var errs []string
+	conf := types.Config{
+		Importer: importer.ForCompiler(fs, "source", nil),
+		Error:    func(err error) { errs = append(errs, err.Error()) },
+	}
+	_, _ = conf.Check(f.Name.Name, fs, []*ast.File{f}, nil)
+	if len(errs) > 0 {
+		t.Fatalf("Expected Go source that compiles, got errors:\n%s", strings.Join(errs, "\n"))
+	}
+}
+
+// declPattern matches declarations such as "func Foo", "func (*T) Foo", "type Foo", "var Foo", and "const Foo"
+var declPattern = regexp.MustCompile(`^\s*(func|type|var|const)\s+(?:\(\s*(\*?)\s*(\w+)\s*\)\s*)?(\w+)\s*$`)
+
+// CheckSourceContainsDecl checks that the given Go source file contains the given top level declaration. The
+// declaration is written as "func Foo", "func (T) Foo" or "func (*T) Foo" for a method, "type Foo", "var Foo", or
+// "const Foo". The source is parsed and inspected, which means that a name that only appears in a comment or
+// string does not match.
+func CheckSourceContainsDecl(src, decl string, t testing.TB) {
+	t.Helper()
+	m := declPattern.FindStringSubmatch(decl)
+	if m == nil || m[3] != "" && m[1] != "func" {
+		t.Fatalf("CheckSourceContainsDecl: illegal declaration %q", decl)
+		return
+	}
+	f, err := parseGoFile(src)
+	if err != nil {
+		t.Fatalf("Expected valid Go source, got error: %s", err.Error())
+		return
+	}
+	if !containsDecl(f, m[1], m[2] == "*", m[3], m[4]) {
+		t.Fatalf("Expected source to contain declaration %q", decl)
+	}
+}
+
+func parseGoFile(src string) (*ast.File, error) {
+	return parser.ParseFile(token.NewFileSet(), "src.go", src, parser.ParseComments)
+}
+
+// containsDecl returns true if the file has a top level declaration of the given kind and name. For methods, recv
+// is the name of the receiver type and ptr tells if the receiver is a pointer.
+func containsDecl(f *ast.File, kind string, ptr bool, recv, name string) bool {
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if kind != "func" || d.Name.Name != name {
+				continue
+			}
+			if recv == "" {
+				if d.Recv == nil {
+					return true
+				}
+				continue
+			}
+			if d.Recv != nil && len(d.Recv.List) == 1 {
+				rptr, rname := receiverType(d.Recv.List[0].Type)
+				if rptr == ptr && rname == recv {
+					return true
+				}
+			}
+		case *ast.GenDecl:
+			if d.Tok.String() != kind {
+				continue
+			}
+			for _, s := range d.Specs {
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					if s.Name.Name == name {
+						return true
+					}
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if n.Name == name {
+							return true
+						}
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// receiverType returns if the receiver type expression is a pointer, and the name of the receiver type (without
+// any type parameters)
+func receiverType(x ast.Expr) (bool, string) {
+	ptr := false
+	if s, ok := x.(*ast.StarExpr); ok {
+		ptr = true
+		x = s.X
+	}
+	switch r := x.(type) {
+	case *ast.IndexExpr:
+		x = r.X
+	case *ast.IndexListExpr:
+		x = r.X
+	}
+	if id, ok := x.(*ast.Ident); ok {
+		return ptr, id.Name
+	}
+	return ptr, ""
+}
+
+// CheckGoSourceEquivalent checks that the expected and got Go source files have the same syntax tree, ignoring
+// comments, formatting, and positions. On failure the kind of node where the trees first diverge is reported
+// together with its position in both sources.
+func CheckGoSourceEquivalent(expected, got string, t testing.TB) {
+	t.Helper()
+	efs := token.NewFileSet()
+	e, err := parser.ParseFile(efs, "expected.go", expected, parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatalf("CheckGoSourceEquivalent: expected is not valid Go source: %s", err.Error())
+		return
+	}
+	gfs := token.NewFileSet()
+	g, err := parser.ParseFile(gfs, "got.go", got, parser.SkipObjectResolution)
+	if err != nil {
+		t.Fatalf("Expected valid Go source, got error: %s", err.Error())
+		return
+	}
+	if en, gn, differs := astDiff(reflect.ValueOf(e), reflect.ValueOf(g), e, g); differs {
+		t.Fatalf("Expected equivalent Go source, but %T at %s differs from %T at %s:\n%s\nexpected:\n%s",
+			gn, gfs.Position(gn.Pos()), en, efs.Position(en.Pos()), nodeText(gfs, gn), nodeText(efs, en))
+	}
+}
+
+var (
+	posType          = reflect.TypeOf(token.NoPos)
+	commentGroupType = reflect.TypeOf((*ast.CommentGroup)(nil))
+	objectType       = reflect.TypeOf((*ast.Object)(nil))
+	scopeType        = reflect.TypeOf((*ast.Scope)(nil))
+)
+
+// astDiff compares the two syntax tree values and returns the innermost nodes enclosing the first difference, and
+// true if there is a difference. Positions, comments, and the results of object resolution are ignored.
+func astDiff(a, b reflect.Value, an, bn ast.Node) (ast.Node, ast.Node, bool) {
+	if a.Type() != b.Type() {
+		return an, bn, true
+	}
+	switch a.Type() {
+	case posType, commentGroupType, objectType, scopeType:
+		return nil, nil, false
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return an, bn, a.IsNil() != b.IsNil()
+		}
+		if a.Kind() == reflect.Interface {
+			return astDiff(a.Elem(), b.Elem(), an, bn)
+		}
+		if n, ok := a.Interface().(ast.Node); ok {
+			an, bn = n, b.Interface().(ast.Node)
+		}
+		return astDiff(a.Elem(), b.Elem(), an, bn)
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return an, bn, true
+		}
+		for i := 0; i < a.Len(); i++ {
+			if en, gn, differs := astDiff(a.Index(i), b.Index(i), an, bn); differs {
+				return en, gn, true
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if !f.IsExported() || a.Type() == reflect.TypeOf(ast.File{}) && f.Name == "Comments" {
+				continue
+			}
+			if en, gn, differs := astDiff(a.Field(i), b.Field(i), an, bn); differs {
+				return en, gn, true
+			}
+		}
+	case reflect.String:
+		return an, bn, a.String() != b.String()
+	case reflect.Bool:
+		return an, bn, a.Bool() != b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return an, bn, a.Int() != b.Int()
+	}
+	return nil, nil, false
+}
+
+// nodeText returns the source of the given node formatted with gofmt
+func nodeText(fs *token.FileSet, n ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fs, n); err != nil {
+		return err.Error()
+	}
+	return buf.String()
+}
@@ -0,0 +1,101 @@
+package testutils
+
+import "testing"
+
+const testGoSource = `package x
+
+// Foo is not func Bar
+func Foo() {}
+
+type T[P any] struct{}
+
+func (T[P]) Value() {}
+
+func (*T[P]) Set() {}
+
+var (
+	a, b = 1, 2
+)
+
+const c = "func Baz"
+`
+
+func TestCheckGofmtEqual(t *testing.T) {
+	CheckGofmtEqual("package x\nfunc Foo()  {}\n", "package x\n\nfunc Foo() {}", t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGofmtEqual("package x\nfunc Foo() {}\n", "package x\nfunc Bar() {}\n", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGofmtEqual("package x\n", "package x\nfunc {", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGofmtEqual("package", "package x\n", ft)
+	})
+}
+
+func TestCheckParsesAsGo(t *testing.T) {
+	CheckParsesAsGo(testGoSource, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckParsesAsGo("package x\nfunc {", ft)
+	})
+}
+
+func TestCheckGeneratedCompiles(t *testing.T) {
+	CheckGeneratedCompiles(testGoSource, t)
+	CheckGeneratedCompiles("package x\n\nimport \"strings\"\n\nvar Upper = strings.ToUpper(\"a\")\n", t)
+
+	m := &messageTB{}
+	CheckGeneratedCompiles("package x\n\nvar a int = \"a\"\n\nfunc f() { b() }\n", m)
+	CheckGeneratedCompiles("package x\n\nimport \"example.com/missing\"\n\nvar _ = missing.X\n", m)
+	CheckGeneratedCompiles("package x\nfunc {", m)
+	CheckEqual(3, len(m.messages), t)
+	CheckEqual("Expected Go source that compiles, got errors:\n"+
+		"src.go:3:13: cannot use \"a\" (untyped string constant) as int value in variable declaration\n"+
+		"src.go:5:12: undefined: b", m.messages[0], t)
+	CheckMatches(`^Expected Go source that compiles, got errors:\nsrc.go:3:8: could not import example.com/missing`, m.messages[1], t)
+	CheckMatches(`^Expected valid Go source, got error: `, m.messages[2], t)
+}
+
+func TestCheckSourceContainsDecl(t *testing.T) {
+	for _, decl := range []string{"func Foo", "type T", "func (T) Value", "func (*T) Set", "var b", "const c"} {
+		CheckSourceContainsDecl(testGoSource, decl, t)
+	}
+	for _, decl := range []string{"func Bar", "func Baz", "func Value", "func (*T) Value", "func (T) Set", "var c", "type X[", "var (T) x"} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckSourceContainsDecl(testGoSource, decl, ft)
+		})
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckSourceContainsDecl("package", "func Foo", ft)
+	})
+}
+
+func TestCheckGoSourceEquivalent(t *testing.T) {
+	CheckGoSourceEquivalent(testGoSource, "package x\nfunc Foo() {\n}\ntype T[P any] struct {}\n"+
+		"func (T[P]) Value() {}\nfunc (*T[P]) Set() {} // set\nvar (\n\ta, b = 1, 2\n)\nconst c = \"func Baz\"\n", t)
+	for _, got := range []string{
+		"package y",
+		"package x\nfunc Foo(a int) {}",
+		"package x\nfunc Foo() { return }",
+		"package x\nconst c = 1",
+		"package x\nvar a = []int{1, 2}",
+		"package x\nvar a = []int{1}",
+		"package x\nvar a = 1",
+		"package x\nfunc {",
+	} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckGoSourceEquivalent("package x\nvar a = []int{1, 3}", got, ft)
+		})
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckGoSourceEquivalent("package", "package x", ft)
+	})
+}
+
+func Test_astDiff_position(t *testing.T) {
+	m := &messageTB{}
+	CheckGoSourceEquivalent("package x\n\nfunc Foo() int {\n\treturn 1 + 2\n}\n",
+		"package x\n\n// Foo returns 3\nfunc Foo() int {\n\treturn 1 + 3\n}\n", m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected equivalent Go source, but \*ast.BasicLit at got.go:5:13 differs from \*ast.BasicLit at expected.go:4:13`, m.messages[0], t)
+}
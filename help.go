@@ -0,0 +1,38 @@
+package testutils
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+)
+
+// CheckHelpOutputGolden calls help to produce the help (usage) output of a command, normalizes it, and compares it
+// with the given golden file using CheckGolden, which means that it supports update mode (see UpdateGolden). The normalization
+// removes ANSI escape sequences, converts line endings to LF, and removes trailing whitespace of each line.
+//
+// For a flag.FlagSet, use FlagSetHelp to produce the help function. For other CLI frameworks, pass a function
+// that sets the output of the command to the given writer and prints its help. For example for a cobra command:
+//
+//	func(w io.Writer) { cmd.SetOut(w); _ = cmd.Help() }
+func CheckHelpOutputGolden(filename string, help func(w io.Writer), t testing.TB) {
+	t.Helper()
+	var b bytes.Buffer
+	help(&b)
+	CheckGolden(filename, NormalizeText(b.String(), StripANSI, NormalizeEOL, TrimTrailingSpace), t)
+}
+
+// FlagSetHelp returns a function that writes the usage of the given flag set to a writer. The usage is produced by
+// the flag set's Usage function if set, and by PrintDefaults otherwise.
+func FlagSetHelp(fs *flag.FlagSet) func(w io.Writer) {
+	return func(w io.Writer) {
+		saved := fs.Output()
+		fs.SetOutput(w)
+		defer fs.SetOutput(saved)
+		if fs.Usage != nil {
+			fs.Usage()
+		} else {
+			fs.PrintDefaults()
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package testutils
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestCheckHelpOutputGolden(t *testing.T) {
+	fs := flag.NewFlagSet("tool", flag.ContinueOnError)
+	fs.Int("count", 1, "number of `times` to run")
+	fs.Bool("verbose", false, "produce verbose output")
+	CheckHelpOutputGolden("testdata/help_flagset.golden", FlagSetHelp(fs), t)
+	if UpdateGolden() {
+		return
+	}
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckHelpOutputGolden("testdata/help_flagset.golden", func(w io.Writer) {
+			fmt.Fprintln(w, "Usage of tool:")
+		}, ft)
+	})
+}
@@ -0,0 +1,42 @@
+package testutils
+
+import (
+	"fmt"
+	"testing"
+)
+
+// WithIndex returns a testing.TB that prefixes all error and fatal messages with "[index]". This gives loop based
+// tests that use the package level checks the same failure prefix as Tester.At, i.e. CheckEqual(e, g, WithIndex(i, t)).
+// Wrapping the result again adds another index, i.e. WithIndex(j, WithIndex(i, t)) produces a prefix like "[2][5]".
+func WithIndex(index int, t testing.TB) testing.TB {
+	prefix := fmt.Sprintf("[%d]", index)
+	if it, ok := t.(indexed); ok {
+		return indexed{TB: it.TB, prefix: it.prefix + prefix}
+	}
+	return indexed{TB: t, prefix: prefix}
+}
+
+type indexed struct {
+	testing.TB
+	prefix string
+}
+
+func (it indexed) Error(args ...interface{}) {
+	it.TB.Helper()
+	it.TB.Error(append([]interface{}{it.prefix}, args...)...)
+}
+
+func (it indexed) Errorf(format string, args ...interface{}) {
+	it.TB.Helper()
+	it.TB.Errorf(it.prefix+" "+format, args...)
+}
+
+func (it indexed) Fatal(args ...interface{}) {
+	it.TB.Helper()
+	it.TB.Fatal(append([]interface{}{it.prefix}, args...)...)
+}
+
+func (it indexed) Fatalf(format string, args ...interface{}) {
+	it.TB.Helper()
+	it.TB.Fatalf(it.prefix+" "+format, args...)
+}
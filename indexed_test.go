@@ -0,0 +1,33 @@
+package testutils
+
+import (
+	"fmt"
+	"testing"
+)
+
+// messageTB records the messages of Errorf and Fatalf
+type messageTB struct {
+	testing.TB
+	messages []string
+}
+
+func (m *messageTB) Helper() {}
+
+func (m *messageTB) Errorf(format string, args ...interface{}) {
+	m.messages = append(m.messages, fmt.Sprintf(format, args...))
+}
+
+func (m *messageTB) Fatalf(format string, args ...interface{}) {
+	m.messages = append(m.messages, fmt.Sprintf(format, args...))
+}
+
+func TestWithIndex(t *testing.T) {
+	ensureFailed(t, func(ft *testing.T) {
+		CheckEqual(1, 2, WithIndex(1, ft))
+	})
+
+	m := &messageTB{}
+	CheckEqual(1, 2, WithIndex(5, WithIndex(2, m)))
+	CheckTrue(false, WithIndex(3, NonFatal(m)))
+	CheckEqual([]string{"[2][5] Expected equal: int 1, got int 2", "[3] Expected: true, got false"}, m.messages, t)
+}
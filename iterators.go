@@ -0,0 +1,98 @@
+//go:build go1.23
+
+package testutils
+
+import (
+	"iter"
+	"testing"
+)
+
+// The checks in this file require Go 1.23 or later since they use the iter package.
+
+// KV is a key and value yielded by an iter.Seq2, used as the expected sequence in CheckIteratorYields2
+type KV[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// CheckIteratorYields checks that the iterator yields the expected values in order and then stops. The values are
+// compared as with CheckEqual. The iterator is stopped after one more value than expected, so an infinite iterator
+// fails instead of hanging. On failure the index of the first difference is reported together with both sequences,
+// and also if the iterator calls yield after it has returned false.
+func CheckIteratorYields[T any](expected []T, seq iter.Seq[T], t testing.TB) {
+	got, misuse := collectSeq(seq, len(expected)+1)
+	if misuse {
+		t.Helper()
+		t.Fatalf("Expected iterator to stop when yield returns false, but it continued to call yield")
+		return
+	}
+	if i, ok := firstSeqDifference(expected, got); !ok {
+		t.Helper()
+		t.Fatalf("Expected iterator to yield %v, got %v%s (first difference at index %d)", expected, got, moreMarker(got, expected), i)
+	}
+}
+
+// CheckIteratorYields2 checks that the iterator yields the expected keys and values in order and then stops. It
+// works like CheckIteratorYields.
+func CheckIteratorYields2[K, V any](expected []KV[K, V], seq iter.Seq2[K, V], t testing.TB) {
+	got, misuse := collectSeq(func(yield func(KV[K, V]) bool) {
+		seq(func(k K, v V) bool { return yield(KV[K, V]{k, v}) })
+	}, len(expected)+1)
+	if misuse {
+		t.Helper()
+		t.Fatalf("Expected iterator to stop when yield returns false, but it continued to call yield")
+		return
+	}
+	if i, ok := firstSeqDifference(expected, got); !ok {
+		t.Helper()
+		t.Fatalf("Expected iterator to yield %v, got %v%s (first difference at index %d)", expected, got, moreMarker(got, expected), i)
+	}
+}
+
+// CheckIteratorTerminates checks that the iterator stops by itself after yielding at most limit values, and that it
+// does not call yield after yield has returned false.
+func CheckIteratorTerminates[T any](seq iter.Seq[T], limit int, t testing.TB) {
+	got, misuse := collectSeq(seq, limit+1)
+	switch {
+	case misuse:
+		t.Helper()
+		t.Fatalf("Expected iterator to stop when yield returns false, but it continued to call yield")
+	case len(got) > limit:
+		t.Helper()
+		t.Fatalf("Expected iterator to terminate after at most %d values, but it yielded more", limit)
+	}
+}
+
+// collectSeq returns at most max values yielded by seq, stopping it by returning false from yield. The returned
+// misuse flag is true if seq called yield after it had returned false.
+func collectSeq[T any](seq iter.Seq[T], max int) (values []T, misuse bool) {
+	stopped := false
+	seq(func(v T) bool {
+		if stopped {
+			misuse = true
+			return false
+		}
+		values = append(values, v)
+		stopped = len(values) >= max
+		return !stopped
+	})
+	return values, misuse
+}
+
+// firstSeqDifference returns the index of the first difference between the two sequences, and true if there is none
+func firstSeqDifference[T any](expected, got []T) (int, bool) {
+	for i := range expected {
+		if i >= len(got) || !valuesEqual(expected[i], got[i]) {
+			return i, false
+		}
+	}
+	return len(expected), len(got) == len(expected)
+}
+
+// moreMarker returns " ..." if the got sequence was stopped after one more value than expected, and may have more
+func moreMarker[T, E any](got []T, expected []E) string {
+	if len(got) > len(expected) {
+		return " ..."
+	}
+	return ""
+}
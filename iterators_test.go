@@ -0,0 +1,66 @@
+//go:build go1.23
+
+package testutils
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func seqCount(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; n < 0 || i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestCheckIteratorYields(t *testing.T) {
+	CheckIteratorYields([]int{0, 1, 2}, seqCount(3), t)
+	CheckIteratorYields([]int{}, seqCount(0), t)
+	CheckIteratorYields([]string{"a", "b"}, slices.Values([]string{"a", "b"}), t)
+
+	m := &messageTB{}
+	CheckIteratorYields([]int{0, 1, 2}, seqCount(2), m)
+	CheckIteratorYields([]int{0, 1}, seqCount(-1), m)
+	CheckIteratorYields([]int{0, 2}, seqCount(2), m)
+	CheckIteratorYields([]int{0}, func(yield func(int) bool) {
+		yield(0)
+		yield(1)
+		yield(2)
+	}, m)
+	CheckEqual([]string{
+		"Expected iterator to yield [0 1 2], got [0 1] (first difference at index 2)",
+		"Expected iterator to yield [0 1], got [0 1 2] ... (first difference at index 2)",
+		"Expected iterator to yield [0 2], got [0 1] (first difference at index 1)",
+		"Expected iterator to stop when yield returns false, but it continued to call yield",
+	}, m.messages, t)
+}
+
+func TestCheckIteratorYields2(t *testing.T) {
+	CheckIteratorYields2([]KV[int, string]{{0, "a"}, {1, "b"}}, slices.All([]string{"a", "b"}), t)
+
+	m := &messageTB{}
+	CheckIteratorYields2([]KV[string, int]{{"a", 2}}, maps.All(map[string]int{"a": 1}), m)
+	CheckEqual([]string{"Expected iterator to yield [{a 2}], got [{a 1}] (first difference at index 0)"}, m.messages, t)
+}
+
+func TestCheckIteratorTerminates(t *testing.T) {
+	CheckIteratorTerminates(seqCount(10), 10, t)
+
+	m := &messageTB{}
+	CheckIteratorTerminates(seqCount(-1), 100, m)
+	CheckIteratorTerminates(func(yield func(int) bool) {
+		for i := 0; i < 3; i++ {
+			yield(i)
+		}
+	}, 1, m)
+	CheckEqual([]string{
+		"Expected iterator to terminate after at most 100 values, but it yielded more",
+		"Expected iterator to stop when yield returns false, but it continued to call yield",
+	}, m.messages, t)
+}
@@ -0,0 +1,258 @@
+package testutils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// CheckJSONSubset checks that the expected JSON document is a subset of the got JSON document. An expected object
+// matches a got object that has all of its keys with matching values (got may have additional keys), an expected
+// array matches a got array of the same length with matching elements, and all other values must be equal. This
+// makes it possible to check only the relevant fields of a large document. On failure the path to the first
+// mismatch is reported.
+func CheckJSONSubset(expected, got string, t testing.TB) {
+	t.Helper()
+	e, err := decodeJSON(expected)
+	if err != nil {
+		t.Fatalf("CheckJSONSubset: expected is not valid JSON: %s", err.Error())
+		return
+	}
+	g, err := decodeJSON(got)
+	if err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %s", got, err.Error())
+		return
+	}
+	if path, reason := jsonSubset(e, g, "$"); path != "" {
+		t.Fatalf("Expected JSON to contain %s, but at %s %s", expected, path, reason)
+	}
+}
+
+// decodeJSON decodes a single JSON value with numbers kept as json.Number
+func decodeJSON(s string) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader([]byte(s)))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if d.More() {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return v, nil
+}
+
+// jsonSubset returns the path to the first value in e that is not matched by g, and the reason why. An empty path
+// is returned when e is a subset of g.
+func jsonSubset(e, g interface{}, path string) (string, string) {
+	switch ev := e.(type) {
+	case map[string]interface{}:
+		gv, ok := g.(map[string]interface{})
+		if !ok {
+			return path, fmt.Sprintf("expected an object, got %s", jsonText(g))
+		}
+		keys := make([]string, 0, len(ev))
+		for k := range ev {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			kp := path + "." + k
+			gkv, ok := gv[k]
+			if !ok {
+				return kp, "is missing"
+			}
+			if p, reason := jsonSubset(ev[k], gkv, kp); p != "" {
+				return p, reason
+			}
+		}
+	case []interface{}:
+		gv, ok := g.([]interface{})
+		if !ok {
+			return path, fmt.Sprintf("expected an array, got %s", jsonText(g))
+		}
+		if len(ev) != len(gv) {
+			return path, fmt.Sprintf("expected an array of length %d, got %d", len(ev), len(gv))
+		}
+		for i := range ev {
+			if p, reason := jsonSubset(ev[i], gv[i], fmt.Sprintf("%s[%d]", path, i)); p != "" {
+				return p, reason
+			}
+		}
+	case json.Number:
+		gv, ok := g.(json.Number)
+		if !ok || !jsonNumbersEqual(ev, gv) {
+			return path, fmt.Sprintf("expected %s, got %s", ev, jsonText(g))
+		}
+	default:
+		if !reflect.DeepEqual(e, g) {
+			return path, fmt.Sprintf("expected %s, got %s", jsonText(e), jsonText(g))
+		}
+	}
+	return "", ""
+}
+
+// jsonNumbersEqual compares numbers by value so that 1, 1.0, and 1e0 are equal
+func jsonNumbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	af, err1 := a.Float64()
+	bf, err2 := b.Float64()
+	return err1 == nil && err2 == nil && af == bf
+}
+
+func jsonText(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// CheckJSONPath checks that the value selected by the given path in the JSON document is equal to the expected
+// value. The expected value is converted to JSON before it is compared, so an expected 1 matches a JSON 1.0, and
+// an expected []string matches a JSON array of strings.
+//
+// The path supports a subset of JSONPath: it starts with an optional `$` followed by any number of `.name`,
+// `['name']`, `[index]`, `.*`, and `[*]` selectors. When the path contains a wildcard, the selected values are
+// compared as an array.
+func CheckJSONPath(doc, path string, expected interface{}, t testing.TB) {
+	t.Helper()
+	selectors, err := parseJSONPath(path)
+	if err != nil {
+		t.Fatalf("CheckJSONPath: %s", err.Error())
+		return
+	}
+	ej, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("CheckJSONPath: expected value %v cannot be converted to JSON: %s", expected, err.Error())
+		return
+	}
+	e, _ := decodeJSON(string(ej))
+	d, err := decodeJSON(doc)
+	if err != nil {
+		t.Fatalf("Expected valid JSON, got %q: %s", doc, err.Error())
+		return
+	}
+	values := []interface{}{d}
+	wildcard := false
+	for _, s := range selectors {
+		wildcard = wildcard || s.wildcard
+		var next []interface{}
+		for _, v := range values {
+			selected, err := s.apply(v)
+			if err != nil {
+				t.Fatalf("Expected JSON path %s to select a value, but %s", path, err.Error())
+				return
+			}
+			next = append(next, selected...)
+		}
+		values = next
+	}
+	var g interface{}
+	if wildcard {
+		if values == nil {
+			values = []interface{}{}
+		}
+		g = values
+	} else {
+		g = values[0]
+	}
+	if p, _ := jsonSubset(e, g, path); p == "" {
+		if p, _ = jsonSubset(g, e, path); p == "" {
+			return
+		}
+	}
+	t.Fatalf("Expected JSON path %s to be %s, got %s", path, ej, jsonText(g))
+}
+
+// jsonSelector is one step of a JSON path. It selects a key, an index, or all elements (when wildcard is true).
+type jsonSelector struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+var jsonPathStep = regexp.MustCompile(`^(?:\.(\*|[^.\[]+)|\[(\*|-?\d+|'(?:[^'\\]|\\.)*')\])`)
+
+func parseJSONPath(path string) ([]jsonSelector, error) {
+	rest := strings.TrimPrefix(path, "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+	var selectors []jsonSelector
+	for rest != "" {
+		m := jsonPathStep.FindStringSubmatch(rest)
+		if m == nil {
+			return nil, fmt.Errorf("illegal JSON path %q at %q", path, rest)
+		}
+		rest = rest[len(m[0]):]
+		s := m[1] + m[2]
+		switch {
+		case s == "*":
+			selectors = append(selectors, jsonSelector{wildcard: true})
+		case m[1] != "":
+			selectors = append(selectors, jsonSelector{key: s})
+		case strings.HasPrefix(s, "'"):
+			k := strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(s[1 : len(s)-1])
+			selectors = append(selectors, jsonSelector{key: k})
+		default:
+			i, _ := strconv.Atoi(s)
+			selectors = append(selectors, jsonSelector{index: i, isIndex: true})
+		}
+	}
+	return selectors, nil
+}
+
+// apply returns the values selected from v. A negative index counts from the end of an array.
+func (s jsonSelector) apply(v interface{}) ([]interface{}, error) {
+	switch {
+	case s.wildcard:
+		switch v := v.(type) {
+		case []interface{}:
+			return v, nil
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			result := make([]interface{}, len(keys))
+			for i, k := range keys {
+				result[i] = v[k]
+			}
+			return result, nil
+		}
+		return nil, fmt.Errorf("%s is not an array or object", jsonText(v))
+	case s.isIndex:
+		a, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not an array", jsonText(v))
+		}
+		i := s.index
+		if i < 0 {
+			i += len(a)
+		}
+		if i < 0 || i >= len(a) {
+			return nil, fmt.Errorf("index %d is out of range for an array of length %d", s.index, len(a))
+		}
+		return []interface{}{a[i]}, nil
+	}
+	o, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not an object", jsonText(v))
+	}
+	e, ok := o[s.key]
+	if !ok {
+		return nil, fmt.Errorf("key %q is missing", s.key)
+	}
+	return []interface{}{e}, nil
+}
@@ -0,0 +1,74 @@
+package testutils
+
+import "testing"
+
+func TestCheckJSONSubset(t *testing.T) {
+	got := `{"id": 1, "name": "a", "tags": ["x", "y"], "owner": {"id": 2, "name": "b"}, "deleted": null}`
+	CheckJSONSubset(`{}`, got, t)
+	CheckJSONSubset(`{"id": 1.0, "owner": {"name": "b"}}`, got, t)
+	CheckJSONSubset(`{"tags": ["x", "y"], "deleted": null}`, got, t)
+	CheckJSONSubset(`[{"a": 1}, 2]`, `[{"a": 1, "b": 2}, 2]`, t)
+
+	for _, expected := range []string{
+		`{"missing": 1}`,
+		`{"id": 2}`,
+		`{"owner": {"id": "2"}}`,
+		`{"tags": ["x"]}`,
+		`{"tags": {"x": 1}}`,
+		`{"name": "a"`,
+	} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckJSONSubset(expected, got, ft)
+		})
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckJSONSubset(`{}`, `{} {}`, ft)
+	})
+}
+
+func Test_jsonSubset_path(t *testing.T) {
+	e, _ := decodeJSON(`{"a": {"b": [1, {"c": true}]}}`)
+	g, _ := decodeJSON(`{"a": {"b": [1, {"c": false}]}}`)
+	path, reason := jsonSubset(e, g, "$")
+	CheckEqual("$.a.b[1].c", path, t)
+	CheckEqual("expected true, got false", reason, t)
+}
+
+func TestCheckJSONPath(t *testing.T) {
+	doc := `{"store": {"books": [{"title": "a", "price": 8}, {"title": "b", "price": 12.5}], "it's": true}, "n": null}`
+	CheckJSONPath(doc, "$.store.books[0].title", "a", t)
+	CheckJSONPath(doc, "store.books[-1].price", 12.5, t)
+	CheckJSONPath(doc, "$.store.books[*].title", []string{"a", "b"}, t)
+	CheckJSONPath(doc, "$.store.books[1]", map[string]interface{}{"title": "b", "price": 12.5}, t)
+	CheckJSONPath(doc, `$['store']['it\'s']`, true, t)
+	CheckJSONPath(doc, "$.n", nil, t)
+	CheckJSONPath(doc, "$.store.books[0].*", []interface{}{8, "a"}, t)
+	CheckJSONPath(doc, "$", map[string]interface{}{"n": nil, "store": map[string]interface{}{
+		"books": []interface{}{map[string]interface{}{"title": "a", "price": 8}, map[string]interface{}{"title": "b", "price": 12.5}},
+		"it's":  true,
+	}}, t)
+	CheckJSONPath(`[]`, "$[*]", []string{}, t)
+
+	for _, c := range []struct {
+		path     string
+		expected interface{}
+	}{
+		{"$.store.books[0].title", "b"},
+		{"$.store.books[2]", nil},
+		{"$.store.books[*].title", []string{"a"}},
+		{"$.store.books[1]", map[string]interface{}{"title": "b"}},
+		{"$.store.missing", nil},
+		{"$.store.books.title", "a"},
+		{"$.n[0]", nil},
+		{"$.n.*", nil},
+		{"$.store..books", nil},
+		{"$.n", func() {}},
+	} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckJSONPath(doc, c.path, c.expected, ft)
+		})
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckJSONPath(`{`, "$", nil, ft)
+	})
+}
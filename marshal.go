@@ -0,0 +1,163 @@
+package testutils
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// CheckJSONMarshalerSymmetry checks that the given value implements json.Marshaler and that the JSON it produces
+// unmarshals back to a value equal to the given value. If the value is a pointer, the round trip is made
+// using a new value of the type it points to. On failure the path to the first asymmetric field is reported.
+func CheckJSONMarshalerSymmetry(value interface{}, t testing.TB) {
+	t.Helper()
+	m, ok := value.(json.Marshaler)
+	if !ok {
+		t.Fatalf("Expected: %T to implement json.Marshaler", value)
+		return
+	}
+	if isNilPointer(value) {
+		t.Fatalf("Expected: a non nil %T", value)
+		return
+	}
+	data, err := m.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON of %T failed: %s", value, err.Error())
+		return
+	}
+	checkRoundTrip(value, data, "JSON", func(v interface{}) error { return json.Unmarshal(data, v) }, t)
+}
+
+// CheckTextMarshalerSymmetry checks that the given value implements encoding.TextMarshaler, that a pointer to its
+// type implements encoding.TextUnmarshaler, and that the text it produces unmarshals back to a value equal to the
+// given value. On failure the path to the first asymmetric field is reported.
+func CheckTextMarshalerSymmetry(value interface{}, t testing.TB) {
+	t.Helper()
+	m, ok := value.(encoding.TextMarshaler)
+	if !ok {
+		t.Fatalf("Expected: %T to implement encoding.TextMarshaler", value)
+		return
+	}
+	if isNilPointer(value) {
+		t.Fatalf("Expected: a non nil %T", value)
+		return
+	}
+	data, err := m.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText of %T failed: %s", value, err.Error())
+		return
+	}
+	checkRoundTrip(value, data, "text", func(v interface{}) error {
+		u, ok := v.(encoding.TextUnmarshaler)
+		if !ok {
+			return fmt.Errorf("%T does not implement encoding.TextUnmarshaler", v)
+		}
+		return u.UnmarshalText(data)
+	}, t)
+}
+
+// isNilPointer returns true if the value is a typed nil pointer
+func isNilPointer(value interface{}) bool {
+	rv := reflect.ValueOf(value)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+func checkRoundTrip(value interface{}, data []byte, format string, unmarshal func(interface{}) error, t testing.TB) {
+	t.Helper()
+	rv := reflect.ValueOf(value)
+	isPtr := rv.Kind() == reflect.Ptr
+	var target reflect.Value
+	if isPtr {
+		target = reflect.New(rv.Type().Elem())
+	} else {
+		target = reflect.New(rv.Type())
+	}
+	if err := unmarshal(target.Interface()); err != nil {
+		t.Fatalf("Unmarshal of %T from %s %q failed: %s", value, format, data, err.Error())
+		return
+	}
+	got := target.Elem()
+	if isPtr {
+		rv = rv.Elem()
+	}
+	if path, ok := firstDifference(rv, got, "value"); ok {
+		t.Fatalf("Expected %T to round trip through %s %q, but %s differs: expected %v, got %v",
+			value, format, data, path, rv, got)
+	}
+}
+
+// ErrorPosition is the expected position of an unmarshal error, as checked by CheckUnmarshalErrorsAt. Fields with
+// zero values are not checked.
+type ErrorPosition struct {
+	// Line is the 1-based line of the error
+	Line int
+	// Offset is the byte offset of the error, as reported by encoding/json
+	Offset int64
+	// Field is the dot separated path of the field of the error, as reported by encoding/json
+	Field string
+}
+
+var errorLine = regexp.MustCompile(`\bline (\d+)\b`)
+
+// CheckUnmarshalErrorsAt checks that unmarshaling the malformed document into target with the given unmarshal
+// function (i.e. json.Unmarshal or yaml.Unmarshal) fails with an error that reports the expected position. The
+// position is taken from a *json.SyntaxError or *json.UnmarshalTypeError (the line is computed from the offset),
+// or from a "line N" in the error message (as produced by i.e. gopkg.in/yaml.v3). This guards the quality of
+// parser error messages.
+func CheckUnmarshalErrorsAt(unmarshal func([]byte, interface{}) error, doc string, target interface{}, at ErrorPosition, t testing.TB) {
+	err := unmarshal([]byte(doc), target)
+	if err == nil {
+		t.Helper()
+		t.Fatalf("Expected unmarshal of %q to fail, but it succeeded", doc)
+		return
+	}
+	var got ErrorPosition
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		got.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		got.Offset, got.Field = typeErr.Offset, typeErr.Field
+	}
+	if got.Offset > 0 {
+		got.Line = strings.Count(doc[:min64(got.Offset, int64(len(doc)))], "\n") + 1
+	} else if m := errorLine.FindStringSubmatch(err.Error()); m != nil {
+		got.Line, _ = strconv.Atoi(m[1])
+	}
+
+	var problems []string
+	if at.Line != 0 && at.Line != got.Line {
+		problems = append(problems, fmt.Sprintf("line %d, got %s", at.Line, positionText(got.Line)))
+	}
+	if at.Offset != 0 && at.Offset != got.Offset {
+		problems = append(problems, fmt.Sprintf("offset %d, got %s", at.Offset, positionText(int(got.Offset))))
+	}
+	if at.Field != "" && at.Field != got.Field {
+		problems = append(problems, fmt.Sprintf("field %q, got %q", at.Field, got.Field))
+	}
+	if len(problems) > 0 {
+		t.Helper()
+		t.Fatalf("Expected unmarshal error at %s (error: %s)", strings.Join(problems, ", and at "), err.Error())
+	}
+}
+
+func positionText(p int) string {
+	if p == 0 {
+		return "none"
+	}
+	return strconv.Itoa(p)
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
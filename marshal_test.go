@@ -0,0 +1,98 @@
+package testutils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+type symmetricPoint struct {
+	X, Y int
+}
+
+func (p symmetricPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.X, p.Y})
+}
+
+func (p *symmetricPoint) UnmarshalJSON(data []byte) error {
+	var xy []int
+	if err := json.Unmarshal(data, &xy); err != nil {
+		return err
+	}
+	p.X, p.Y = xy[0], xy[1]
+	return nil
+}
+
+type asymmetricPoint struct {
+	X, Y int
+}
+
+func (p asymmetricPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]int{"X": p.X})
+}
+
+type upperText string
+
+func (u upperText) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(u))), nil
+}
+
+func (u *upperText) UnmarshalText(data []byte) error {
+	*u = upperText(data)
+	return nil
+}
+
+func TestCheckJSONMarshalerSymmetry(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckJSONMarshalerSymmetry(symmetricPoint{1, 2}, ft)
+	})
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckJSONMarshalerSymmetry(&symmetricPoint{1, 2}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckJSONMarshalerSymmetry(asymmetricPoint{1, 2}, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckJSONMarshalerSymmetry(1, ft)
+	})
+
+	m := &messageTB{}
+	CheckJSONMarshalerSymmetry((*symmetricPoint)(nil), m)
+	CheckEqual([]string{"Expected: a non nil *testutils.symmetricPoint"}, m.messages, t)
+}
+
+func TestCheckTextMarshalerSymmetry(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckTextMarshalerSymmetry(upperText("ABC"), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTextMarshalerSymmetry(upperText("abc"), ft)
+	})
+
+	m := &messageTB{}
+	CheckTextMarshalerSymmetry((*upperText)(nil), m)
+	CheckEqual([]string{"Expected: a non nil *testutils.upperText"}, m.messages, t)
+}
+
+func TestCheckUnmarshalErrorsAt(t *testing.T) {
+	type config struct {
+		Server struct {
+			Port int
+		}
+	}
+	doc := "{\n  \"Server\": {\n    \"Port\": \"80\"\n  }\n}"
+	CheckUnmarshalErrorsAt(json.Unmarshal, doc, &config{}, ErrorPosition{Line: 3, Field: "Server.Port"}, t)
+	CheckUnmarshalErrorsAt(json.Unmarshal, "{\n\"a\": 1,\n}", &config{}, ErrorPosition{Line: 3, Offset: 11}, t)
+	CheckUnmarshalErrorsAt(yaml.Unmarshal, "server:\n  port: [1]\n", &struct{ Server struct{ Port int } }{}, ErrorPosition{Line: 2}, t)
+
+	m := &messageTB{}
+	CheckUnmarshalErrorsAt(json.Unmarshal, `{}`, &config{}, ErrorPosition{Line: 1}, m)
+	CheckUnmarshalErrorsAt(json.Unmarshal, doc, &config{}, ErrorPosition{Line: 2, Field: "Port"}, m)
+	CheckUnmarshalErrorsAt(yaml.Unmarshal, "a: [", &config{}, ErrorPosition{Offset: 3}, m)
+	CheckEqual(3, len(m.messages), t)
+	CheckEqual(`Expected unmarshal of "{}" to fail, but it succeeded`, m.messages[0], t)
+	CheckMatches(`^Expected unmarshal error at line 2, got 3, and at field "Port", got "Server.Port" \(error: json: `, m.messages[1], t)
+	CheckEqual(`Expected unmarshal error at offset 3, got none (error: yaml: line 1: did not find expected node content)`, m.messages[2], t)
+}
@@ -0,0 +1,53 @@
+package testutils
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Param is a named dimension of a parameter matrix run by RunMatrix
+type Param struct {
+	Name   string
+	Values []interface{}
+}
+
+// P returns a Param with the given name and values
+func P[T any](name string, values ...T) Param {
+	vs := make([]interface{}, len(values))
+	for i, v := range values {
+		vs[i] = v
+	}
+	return Param{Name: name, Values: vs}
+}
+
+// Combination holds one value per Param name for one combination in a parameter matrix
+type Combination map[string]interface{}
+
+// RunMatrix runs fn once for every combination of the values of the given params. Each combination runs as a
+// nested subtest of t with one level per param named "<name>=<value>", which gives subtest names like
+// "size=10/mode=fast". The Tester given to fn has the sequential number of the combination as its index.
+func RunMatrix(params []Param, fn func(tt Tester, c Combination), t testing.TB) {
+	t.Helper()
+	n := 0
+	runMatrix(params, Combination{}, &n, fn, NewTester(t))
+}
+
+func runMatrix(params []Param, c Combination, n *int, fn func(tt Tester, c Combination), tt Tester) {
+	if len(params) == 0 {
+		combination := make(Combination, len(c))
+		for k, v := range c {
+			combination[k] = v
+		}
+		fn(tt.At(*n), combination)
+		*n++
+		return
+	}
+	p := params[0]
+	for _, v := range p.Values {
+		c[p.Name] = v
+		tt.Run(fmt.Sprintf("%s=%v", p.Name, v), func(ct Tester) {
+			runMatrix(params[1:], c, n, fn, ct)
+		})
+	}
+	delete(c, p.Name)
+}
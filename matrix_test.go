@@ -0,0 +1,17 @@
+package testutils
+
+import "testing"
+
+func TestRunMatrix(t *testing.T) {
+	var seen []string
+	var indexes []int
+	RunMatrix([]Param{P("size", 1, 10), P("mode", "fast", "slow")}, func(tt Tester, c Combination) {
+		seen = append(seen, tt.(*tester).name)
+		indexes = append(indexes, tt.(*tester).indices[0])
+		tt.CheckEqual(2, len(c))
+	}, t)
+	CheckEqual([]string{
+		"size=1/mode=fast", "size=1/mode=slow", "size=10/mode=fast", "size=10/mode=slow",
+	}, seen, t)
+	CheckEqual([]int{0, 1, 2, 3}, indexes, t)
+}
@@ -0,0 +1,36 @@
+package testutils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Normalizer transforms text before it is compared, typically to remove differences that are not significant
+type Normalizer func(s string) string
+
+// NormalizeText returns the text after passing it through each of the given normalizers in order
+func NormalizeText(s string, normalizers ...Normalizer) string {
+	for _, n := range normalizers {
+		s = n(s)
+	}
+	return s
+}
+
+// NormalizeEOL replaces CRLF and CR line endings with LF
+func NormalizeEOL(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
+
+var trailingSpace = regexp.MustCompile(`[ \t]+(\r?\n|\r|$)`)
+
+// TrimTrailingSpace removes spaces and tabs at the end of each line, keeping the LF, CRLF, or CR line endings
+func TrimTrailingSpace(s string) string {
+	return trailingSpace.ReplaceAllString(s, "$1")
+}
+
+var spaceRun = regexp.MustCompile(`[ \t]+`)
+
+// CollapseSpaces replaces each run of spaces and tabs with a single space
+func CollapseSpaces(s string) string {
+	return spaceRun.ReplaceAllString(s, " ")
+}
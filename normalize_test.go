@@ -0,0 +1,15 @@
+package testutils
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	CheckEqual("a\nb\nc", NormalizeEOL("a\r\nb\rc"), t)
+	CheckEqual("a\n b\n\nc", TrimTrailingSpace("a  \n b\t\n \nc "), t)
+	CheckEqual("a\r\n b\r\n\r\nc\rd", TrimTrailingSpace("a  \r\n b\t\r\n \r\nc \rd\t"), t)
+	CheckEqual("a\nb", NormalizeText("\x1b[1ma\x1b[0m  \r\nb", StripANSI, NormalizeEOL, TrimTrailingSpace), t)
+	CheckEqual("x", NormalizeText("x"), t)
+}
+
+func TestCollapseSpaces(t *testing.T) {
+	CheckEqual("a b c ", CollapseSpaces("a  b\t \tc  "), t)
+}
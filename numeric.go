@@ -0,0 +1,168 @@
+package testutils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// CheckSeriesApproxEqual checks that the got series has the same length as the expected series, and that each
+// value differs by at most tol from the expected value at the same index. Two NaN values are considered equal. On
+// failure the number of values outside of the tolerance and the index of the worst deviation are reported.
+func CheckSeriesApproxEqual(expected, got []float64, tol float64, t testing.TB) {
+	if len(expected) != len(got) {
+		t.Helper()
+		t.Fatalf("Expected a series of length %d, got %d", len(expected), len(got))
+		return
+	}
+	worst, worstDev, count := -1, 0.0, 0
+	for i, e := range expected {
+		dev := deviation(e, got[i])
+		if dev <= tol {
+			continue
+		}
+		count++
+		if worst < 0 || dev > worstDev {
+			worst, worstDev = i, dev
+		}
+	}
+	if count > 0 {
+		t.Helper()
+		t.Fatalf("Expected series to be equal within %g, got %d of %d values outside, the worst at index %d: expected %g, got %g (deviation %g)",
+			tol, count, len(expected), worst, expected[worst], got[worst], worstDev)
+	}
+}
+
+// deviation returns the absolute difference between the two values. It is zero for two NaN values, and
+// infinite if only one is NaN.
+func deviation(a, b float64) float64 {
+	switch {
+	case math.IsNaN(a) && math.IsNaN(b):
+		return 0
+	case math.IsNaN(a) || math.IsNaN(b):
+		return math.Inf(1)
+	case a == b:
+		// equal infinities
+		return 0
+	}
+	return math.Abs(a - b)
+}
+
+// CheckMatrixEqual checks that the got matrix has the same shape as the expected matrix, and that each value
+// differs by at most tol from the expected value at the same row and column. Two NaN values are considered equal.
+// On failure a shape mismatch, or the number of values outside of the tolerance and the coordinates of the worst
+// deviation, are reported.
+func CheckMatrixEqual(expected, got [][]float64, tol float64, t testing.TB) {
+	if len(expected) != len(got) {
+		t.Helper()
+		t.Fatalf("Expected a matrix with %d rows, got %d", len(expected), len(got))
+		return
+	}
+	for r := range expected {
+		if len(expected[r]) != len(got[r]) {
+			t.Helper()
+			t.Fatalf("Expected row %d of the matrix to have %d columns, got %d", r, len(expected[r]), len(got[r]))
+			return
+		}
+	}
+	worstRow, worstCol, worstDev, count, total := -1, -1, 0.0, 0, 0
+	for r, row := range expected {
+		total += len(row)
+		for c, e := range row {
+			dev := deviation(e, got[r][c])
+			if dev <= tol {
+				continue
+			}
+			count++
+			if worstRow < 0 || dev > worstDev {
+				worstRow, worstCol, worstDev = r, c, dev
+			}
+		}
+	}
+	if count > 0 {
+		t.Helper()
+		t.Fatalf("Expected matrix to be equal within %g, got %d of %d values outside, the worst at [%d][%d]: expected %g, got %g (deviation %g)",
+			tol, count, total, worstRow, worstCol, expected[worstRow][worstCol], got[worstRow][worstCol], worstDev)
+	}
+}
+
+// CheckHistogramBuckets checks that the got histogram has the same bucket bounds as the expected histogram, and that
+// the count of each bucket differs by at most tol from the expected count. The histograms are given as maps from the
+// upper bound of each bucket to its count. On failure the delta of each bucket outside of the tolerance, as well as
+// missing and unexpected buckets, are reported in bound order.
+func CheckHistogramBuckets(expected, got map[float64]uint64, tol uint64, t testing.TB) {
+	bounds := make([]float64, 0, len(expected)+len(got))
+	for b := range expected {
+		bounds = append(bounds, b)
+	}
+	for b := range got {
+		if _, ok := expected[b]; !ok {
+			bounds = append(bounds, b)
+		}
+	}
+	sort.Float64s(bounds)
+
+	var diffs []string
+	for _, b := range bounds {
+		e, eok := expected[b]
+		g, gok := got[b]
+		switch {
+		case !gok:
+			diffs = append(diffs, fmt.Sprintf("  le %g: missing bucket, expected %d", b, e))
+		case !eok:
+			diffs = append(diffs, fmt.Sprintf("  le %g: unexpected bucket with %d", b, g))
+		case e > g && e-g > tol || g > e && g-e > tol:
+			diffs = append(diffs, fmt.Sprintf("  le %g: expected %d, got %d (delta %+d)", b, e, g, int64(g)-int64(e)))
+		}
+	}
+	if len(diffs) > 0 {
+		t.Helper()
+		t.Fatalf("Expected histogram buckets to be equal within %d, %d of %d buckets differ:\n%s",
+			tol, len(diffs), len(bounds), strings.Join(diffs, "\n"))
+	}
+}
+
+// CheckWeightedDistribution checks that the observed share of draws for each key is within tol of the share given
+// by its configured weight, i.e. that |draws[k]/total draws - weights[k]/total weight| <= tol. A key that was drawn
+// but has no weight is expected to have a share of 0. On failure the expected and observed share of each key
+// outside of the tolerance are reported.
+func CheckWeightedDistribution[K comparable](draws map[K]int, weights map[K]float64, tol float64, t testing.TB) {
+	totalDraws := 0
+	for _, n := range draws {
+		totalDraws += n
+	}
+	totalWeight := 0.0
+	for _, w := range weights {
+		totalWeight += w
+	}
+	if totalDraws == 0 || totalWeight <= 0 {
+		t.Helper()
+		t.Fatalf("Expected draws and weights with positive totals, got %d draws and a total weight of %g", totalDraws, totalWeight)
+		return
+	}
+	keys := make([]K, 0, len(weights)+len(draws))
+	for k := range weights {
+		keys = append(keys, k)
+	}
+	for k := range draws {
+		if _, ok := weights[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+
+	var diffs []string
+	for _, k := range keys {
+		expected := weights[k] / totalWeight
+		got := float64(draws[k]) / float64(totalDraws)
+		if math.Abs(expected-got) > tol {
+			diffs = append(diffs, fmt.Sprintf("  %v: expected share %.4f, got %.4f (%d of %d draws)", k, expected, got, draws[k], totalDraws))
+		}
+	}
+	if len(diffs) > 0 {
+		t.Helper()
+		t.Fatalf("Expected draws to follow the weights within %g, %d of %d keys differ:\n%s", tol, len(diffs), len(keys), strings.Join(diffs, "\n"))
+	}
+}
@@ -0,0 +1,69 @@
+package testutils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCheckSeriesApproxEqual(t *testing.T) {
+	CheckSeriesApproxEqual([]float64{1, 2, math.NaN(), math.Inf(1)}, []float64{1.05, 1.95, math.NaN(), math.Inf(1)}, 0.1, t)
+	CheckSeriesApproxEqual(nil, []float64{}, 0, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckSeriesApproxEqual([]float64{1}, []float64{1, 2}, 0.1, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckSeriesApproxEqual([]float64{1}, []float64{math.NaN()}, 0.1, ft)
+	})
+
+	m := &messageTB{}
+	CheckSeriesApproxEqual([]float64{1, 2, 3, 4}, []float64{1.5, 2, 4, 4}, 0.1, m)
+	CheckEqual([]string{"Expected series to be equal within 0.1, got 2 of 4 values outside, the worst at index 2: expected 3, got 4 (deviation 1)"}, m.messages, t)
+}
+
+func TestCheckMatrixEqual(t *testing.T) {
+	CheckMatrixEqual([][]float64{{1, 2}, {3, 4}}, [][]float64{{1.01, 2}, {3, 3.99}}, 0.05, t)
+	CheckMatrixEqual(nil, [][]float64{}, 0, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckMatrixEqual([][]float64{{1, 2}}, [][]float64{{1, 2}, {3, 4}}, 0, ft)
+	})
+
+	m := &messageTB{}
+	CheckMatrixEqual([][]float64{{1, 2}, {3, 4}}, [][]float64{{1, 2}, {3}}, 0, m)
+	CheckMatrixEqual([][]float64{{1, 2}, {3, 4}}, [][]float64{{1, 2.5}, {3, 6}}, 0.1, m)
+	CheckEqual([]string{
+		"Expected row 1 of the matrix to have 2 columns, got 1",
+		"Expected matrix to be equal within 0.1, got 2 of 4 values outside, the worst at [1][1]: expected 4, got 6 (deviation 2)",
+	}, m.messages, t)
+}
+
+func TestCheckHistogramBuckets(t *testing.T) {
+	inf := math.Inf(1)
+	CheckHistogramBuckets(map[float64]uint64{0.1: 10, 1: 5, inf: 1}, map[float64]uint64{0.1: 11, 1: 4, inf: 1}, 1, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckHistogramBuckets(map[float64]uint64{0.1: 10}, map[float64]uint64{0.1: 12}, 1, ft)
+	})
+
+	m := &messageTB{}
+	CheckHistogramBuckets(
+		map[float64]uint64{0.1: 10, 0.5: 3, 1: 5, inf: 1},
+		map[float64]uint64{0.1: 7, 0.25: 2, 1: 5, inf: 4},
+		1, m)
+	CheckEqual([]string{"Expected histogram buckets to be equal within 1, 4 of 5 buckets differ:\n" +
+		"  le 0.1: expected 10, got 7 (delta -3)\n" +
+		"  le 0.25: unexpected bucket with 2\n" +
+		"  le 0.5: missing bucket, expected 3\n" +
+		"  le +Inf: expected 1, got 4 (delta +3)"}, m.messages, t)
+}
+
+func TestCheckWeightedDistribution(t *testing.T) {
+	CheckWeightedDistribution(map[string]int{"a": 748, "b": 252}, map[string]float64{"a": 3, "b": 1}, 0.01, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckWeightedDistribution(map[int]int{}, map[int]float64{1: 1}, 0.01, ft)
+	})
+
+	m := &messageTB{}
+	CheckWeightedDistribution(map[string]int{"a": 500, "b": 400, "c": 100}, map[string]float64{"a": 50, "b": 50}, 0.05, m)
+	CheckEqual([]string{"Expected draws to follow the weights within 0.05, 2 of 3 keys differ:\n" +
+		"  b: expected share 0.5000, got 0.4000 (400 of 1000 draws)\n" +
+		"  c: expected share 0.0000, got 0.1000 (100 of 1000 draws)"}, m.messages, t)
+}
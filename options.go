@@ -0,0 +1,161 @@
+package testutils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// defaultDiffLimit is the default number of consecutive unequal lines shown by CheckStringSlicesEqual
+const defaultDiffLimit = 2
+
+// DiffLimitEnv is the name of an environment variable that sets the number of consecutive unequal lines shown
+// in a diff when the DiffLimit option is not used. The value is a number, or "all" to show all lines, which is
+// useful in CI logs.
+const DiffLimitEnv = "TESTUTILS_DIFF_LIMIT"
+
+// TesterOption is an option given to NewTesterWith
+type TesterOption func(tt *tester)
+
+// NewTesterWith returns a new tester configured with the given options
+func NewTesterWith(t testing.TB, opts ...TesterOption) Tester {
+	tt := &tester{t: t}
+	for _, opt := range opts {
+		opt(tt)
+	}
+	return tt
+}
+
+// FailFast controls if a failed check stops the test (the default) or if it is reported with Errorf and the
+// test continues. FailFast(false) has the same effect as calling Expect() on the tester.
+func FailFast(failFast bool) TesterOption {
+	return func(tt *tester) {
+		tt.nonFatal = !failFast
+	}
+}
+
+// NoColor makes diffs of text be produced without ANSI color codes. This is also the case without this option when
+// the NO_COLOR or TESTUTILS_NOCOLOR environment variables are set, or when output is not written to a terminal.
+func NoColor() TesterOption {
+	return func(tt *tester) {
+		tt.noColor = true
+	}
+}
+
+// AssumeTerminal makes the tester produce diffs as if test output is written to a terminal, which means that diffs
+// of text are colored unless turned off with the NoColor option, or the NO_COLOR or TESTUTILS_NOCOLOR environment
+// variables. This is typically used together with WithFakeTerminal.
+func AssumeTerminal() TesterOption {
+	return func(tt *tester) {
+		tt.terminal = true
+	}
+}
+
+// UnifiedDiff makes CheckTextEqual and CheckStringSlicesEqual report differences as a unified diff (with
+// ---/+++ headers and @@ hunks) that can be read by patch aware tools and CI systems. The diff transforms the
+// expected text into the produced text.
+func UnifiedDiff() TesterOption {
+	return func(tt *tester) {
+		tt.unified = true
+	}
+}
+
+// DiffLimit sets the number of consecutive unequal lines shown in a diff before it is cut short. A limit of
+// zero or less shows all lines. The default is 2, or the value of the environment variable named by DiffLimitEnv.
+func DiffLimit(n int) TesterOption {
+	return func(tt *tester) {
+		if n <= 0 {
+			n = -1
+		}
+		tt.diffLimit = n
+	}
+}
+
+// ShowAllDiffs makes diffs show all lines instead of being cut short, same as DiffLimit(0)
+func ShowAllDiffs() TesterOption {
+	return DiffLimit(0)
+}
+
+// Normalize adds normalizers that are applied to both the expected and the got text before they are compared by
+// CheckTextEqual, CheckTextLinesEqual, CheckTextEqualStripANSI, and CheckStringSlicesEqual (where each string is
+// normalized). Normalizers are applied in the order they are added.
+func Normalize(normalizers ...Normalizer) TesterOption {
+	return func(tt *tester) {
+		tt.normalizers = append(tt.normalizers, normalizers...)
+	}
+}
+
+// IgnoreTrailingSpace makes text comparisons ignore spaces and tabs at the end of lines
+func IgnoreTrailingSpace() TesterOption {
+	return Normalize(TrimTrailingSpace)
+}
+
+// IgnoreSpaceRuns makes text comparisons treat runs of spaces and tabs as a single space
+func IgnoreSpaceRuns() TesterOption {
+	return Normalize(CollapseSpaces)
+}
+
+// IgnoreLineEndings makes text comparisons treat CRLF, CR, and LF line endings as equal
+func IgnoreLineEndings() TesterOption {
+	return Normalize(NormalizeEOL)
+}
+
+// IgnoreANSI makes text comparisons remove ANSI escape sequences from both the expected and the got text
+func IgnoreANSI() TesterOption {
+	return Normalize(StripANSI)
+}
+
+// NilEqualsEmpty makes CheckEqual, CheckNotEqual, and CheckAll consider a nil slice or map equal to an empty
+// slice or map of the same type, also when nested in other values. This avoids noisy failures for values that have
+// been through i.e. a JSON round trip or a database scan. A single check can use it as
+// NewTesterWith(t, NilEqualsEmpty()).CheckEqual(expected, got).
+func NilEqualsEmpty() TesterOption {
+	return func(tt *tester) {
+		tt.nilEqualsEmpty = true
+	}
+}
+
+// IgnoreTimeZone makes CheckEqual, CheckNotEqual, and CheckAll consider time.Time values that are the same instant
+// equal even if they have different locations, also when nested in other values. When tests run in verbose mode,
+// each such pair of values is noted in the test log. It also makes CheckTimesEqual compare the times in UTC.
+func IgnoreTimeZone() TesterOption {
+	return func(tt *tester) {
+		tt.ignoreTimeZone = true
+	}
+}
+
+// MessagePrefix sets a prefix that is prepended to all failure messages
+func MessagePrefix(prefix string) TesterOption {
+	return func(tt *tester) {
+		tt.msgPrefix = prefix
+	}
+}
+
+// maxDiffLines returns the number of consecutive unequal lines to show in a diff, or 0 for no limit
+func (tt *tester) maxDiffLines() int {
+	switch {
+	case tt.diffLimit < 0:
+		return 0
+	case tt.diffLimit == 0:
+		return envDiffLimit()
+	default:
+		return tt.diffLimit
+	}
+}
+
+// envDiffLimit returns the diff limit set in the environment variable named by DiffLimitEnv, or the default
+// limit if the variable is not set or does not have a valid value.
+func envDiffLimit() int {
+	v := strings.TrimSpace(os.Getenv(DiffLimitEnv))
+	if strings.EqualFold(v, "all") {
+		return 0
+	}
+	if n, err := strconv.Atoi(v); err == nil {
+		if n < 0 {
+			n = 0
+		}
+		return n
+	}
+	return defaultDiffLimit
+}
@@ -0,0 +1,147 @@
+package testutils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTesterWith(t *testing.T) {
+	continued := false
+	ensureFailed(t, func(ft *testing.T) {
+		tt := NewTesterWith(ft, FailFast(false))
+		tt.CheckEqual(1, 2)
+		continued = true
+	})
+	CheckTrue(continued, t)
+
+	tt := NewTesterWith(t, NoColor(), DiffLimit(0), MessagePrefix("config: ")).(*tester)
+	CheckTrue(tt.noColor, t)
+	CheckEqual(0, tt.maxDiffLines(), t)
+	CheckEqual(2, NewTester(t).(*tester).maxDiffLines(), t)
+	CheckEqual(5, NewTesterWith(t, DiffLimit(5)).(*tester).maxDiffLines(), t)
+
+	tt.soft = &softFailures{}
+	tt.At(1).CheckTextEqual("abc", "abd")
+	CheckEqual([]string{"config: [1] strings not equal - see diff:\nab[-c-]{+d+}"}, tt.soft.messages, t)
+}
+
+func TestAssumeTerminal(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv(NoColorEnv, "")
+	tt := NewTesterWith(t, AssumeTerminal()).(*tester)
+	tt.soft = &softFailures{}
+	tt.CheckTextEqual("abc", "abd")
+	CheckEqual([]string{"strings not equal - see diff:\nab\x1b[31mc\x1b[0m\x1b[32md\x1b[0m"}, tt.soft.messages, t)
+
+	tt = NewTesterWith(t, AssumeTerminal(), NoColor()).(*tester)
+	tt.soft = &softFailures{}
+	tt.CheckTextEqual("abc", "abd")
+	CheckEqual([]string{"strings not equal - see diff:\nab[-c-]{+d+}"}, tt.soft.messages, t)
+}
+
+func Test_produceDiffLimit(t *testing.T) {
+	expected := []string{"a", "b", "c", "d", "e"}
+	got := []string{"1", "2", "3", "4", "5"}
+	diff, ok := produceDiff(expected, got, 2)
+	CheckFalse(ok, t)
+	CheckTrue(strings.HasSuffix(diff, "... stopping after 2 unequal lines"), t)
+	diff, _ = produceDiff(expected, got, 0)
+	CheckEqual(10, len(strings.Split(diff, "\n")), t)
+}
+
+func Test_envDiffLimit(t *testing.T) {
+	t.Setenv(DiffLimitEnv, "all")
+	CheckEqual(0, NewTester(t).(*tester).maxDiffLines(), t)
+	CheckEqual(3, NewTesterWith(t, DiffLimit(3)).(*tester).maxDiffLines(), t)
+	t.Setenv(DiffLimitEnv, "7")
+	CheckEqual(7, NewTester(t).(*tester).maxDiffLines(), t)
+	t.Setenv(DiffLimitEnv, "many")
+	CheckEqual(2, NewTester(t).(*tester).maxDiffLines(), t)
+	CheckEqual(0, NewTesterWith(t, ShowAllDiffs()).(*tester).maxDiffLines(), t)
+}
+
+func TestNormalizeOptions(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		tt := NewTesterWith(ft, IgnoreLineEndings(), IgnoreTrailingSpace(), IgnoreSpaceRuns())
+		tt.CheckTextEqual("a b\nc\n", "a   b  \r\nc\r\n")
+		tt.CheckTextLinesEqual("a b\nc\n", "a\tb\r\nc \r\n")
+		tt.CheckStringSlicesEqual([]string{"a b", "c"}, []string{"a  b\r", "c  "})
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, IgnoreTrailingSpace()).CheckTextEqual("a\nb", "a\r\nb")
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, IgnoreLineEndings()).CheckStringSlicesEqual([]string{"a"}, []string{"a "})
+	})
+}
+
+func TestIgnoreANSI(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, IgnoreANSI()).CheckTextEqual("\x1b[31mred\x1b[0m\n", "red\n")
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, IgnoreANSI()).CheckTextEqual("\x1b[31mred\x1b[0m\n", "blue\n")
+	})
+}
+
+func TestNilEqualsEmpty(t *testing.T) {
+	type row struct {
+		Tags  []string
+		Attrs map[string]int
+	}
+	tt := NewTesterWith(t, NilEqualsEmpty())
+	tt.CheckEqual([]int(nil), []int{})
+	tt.CheckEqual(map[string]int{}, map[string]int(nil))
+	tt.CheckEqual([]row{{Tags: nil, Attrs: map[string]int{}}}, []row{{Tags: []string{}}})
+	tt.CheckNotEqual([]int(nil), []int{0})
+	tt.CheckNotEqual([]int(nil), []string{})
+	tt.CheckAll(Pair{"a", []string(nil), []string{}}, Pair{"b", 1, int64(1)})
+	tt.CheckEqualAndNoError(row{}, row{Tags: []string{}}, nil)
+	tt.Run("child", func(ct Tester) {
+		ct.CheckEqual(row{}, row{Tags: []string{}})
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckEqual([]int(nil), []int{})
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckEqualAndNoError([]int(nil), []int{}, nil)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, NilEqualsEmpty()).CheckNotEqual(row{}, row{Attrs: map[string]int{}})
+	})
+	// values deeper than any depth limit are compared all the way
+	tt.CheckNotEqual(diffList(150, 149), diffList(150, 0))
+	ensureFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, NilEqualsEmpty()).CheckEqual(diffList(150, 149), diffList(150, 0))
+	})
+}
+
+func TestIgnoreTimeZone(t *testing.T) {
+	type event struct {
+		At time.Time
+	}
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cet := utc.In(time.FixedZone("CET", 3600))
+	tt := NewTesterWith(t, IgnoreTimeZone())
+	tt.CheckEqual(utc, cet)
+	tt.CheckEqual(event{utc}, event{cet})
+	tt.CheckNotEqual(event{utc}, event{utc.Add(time.Second)})
+	tt.CheckEqualAndNoError(event{utc}, event{cet}, nil)
+
+	ensureFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckEqual(event{utc}, event{cet})
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, NilEqualsEmpty()).CheckEqual(event{utc}, event{cet})
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckEqualAndNoError(event{utc}, event{cet}, nil)
+	})
+	// values deeper than any depth limit are compared all the way
+	tt.CheckNotEqual(diffList(150, 149), diffList(150, 0))
+	ensureFailed(t, func(ft *testing.T) {
+		NewTesterWith(ft, IgnoreTimeZone()).CheckEqual(diffList(150, 149), diffList(150, 0))
+	})
+}
@@ -0,0 +1,109 @@
+package testutils
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// maxInputLength is the max length of an input shown in a failure message
+const maxInputLength = 80
+
+// CheckPanicFreeForAllInputs calls fn with each of the given inputs, followed by generated edge case values of T
+// (see EdgeValues), and checks that no call panics. On failure each offending input is reported together with
+// the value it panicked with.
+func CheckPanicFreeForAllInputs[T any](inputs []T, fn func(T), t testing.TB) {
+	var failures []string
+	for _, in := range append(append([]T{}, inputs...), EdgeValues[T]()...) {
+		if r := callRecover(func() { fn(in) }); r != nil {
+			input := fmt.Sprintf("%#v", in)
+			if len(input) > maxInputLength {
+				input = input[:maxInputLength] + "..."
+			}
+			failures = append(failures, fmt.Sprintf("  %s: %v", input, r))
+		}
+	}
+	if len(failures) > 0 {
+		t.Helper()
+		t.Fatalf("Expected no panics, got %d:\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+}
+
+func callRecover(f func()) (r interface{}) {
+	defer func() { r = recover() }()
+	f()
+	return nil
+}
+
+// EdgeValues returns values of T that commonly expose bugs: zero, one, minus one, and the limits for numbers,
+// NaN and the infinities for floats, empty, blank, NUL, invalid UTF-8, and very long strings, nil and empty
+// slices and maps, and nil pointers as well as pointers to each edge value of the pointed to type. Types for which
+// no edge values are known (such as structs) only have their zero value.
+func EdgeValues[T any]() []T {
+	values := edgeValues(reflect.TypeOf((*T)(nil)).Elem(), 2)
+	result := make([]T, len(values))
+	for i, v := range values {
+		result[i], _ = v.Interface().(T) // a nil interface value is the zero T
+	}
+	return result
+}
+
+func edgeValues(typ reflect.Type, depth int) []reflect.Value {
+	var values []interface{}
+	switch typ.Kind() {
+	case reflect.Bool:
+		values = []interface{}{true}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := typ.Bits()
+		values = []interface{}{int64(1), int64(-1), int64(math.MinInt64 >> (64 - bits)), int64(math.MaxInt64 >> (64 - bits))}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		values = []interface{}{uint64(1), uint64(math.MaxUint64 >> (64 - typ.Bits()))}
+	case reflect.Float32:
+		values = []interface{}{math.Copysign(0, -1), 1.0, -1.0, math.MaxFloat32, -math.MaxFloat32,
+			math.SmallestNonzeroFloat32, math.Inf(1), math.Inf(-1), math.NaN()}
+	case reflect.Float64:
+		values = []interface{}{math.Copysign(0, -1), 1.0, -1.0, math.MaxFloat64, -math.MaxFloat64,
+			math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1), math.NaN()}
+	case reflect.String:
+		values = []interface{}{" ", "\x00", "\xff\xfe", "\u00e9\u4e16\U0001F600", strings.Repeat("x", 1<<16)}
+	}
+	// the zero value is always the first edge value
+	result := []reflect.Value{reflect.Zero(typ)}
+	for _, v := range values {
+		rv := reflect.New(typ).Elem()
+		switch v := v.(type) {
+		case bool:
+			rv.SetBool(v)
+		case int64:
+			rv.SetInt(v)
+		case uint64:
+			rv.SetUint(v)
+		case float64:
+			rv.SetFloat(v)
+		case string:
+			rv.SetString(v)
+		}
+		result = append(result, rv)
+	}
+	if depth <= 0 {
+		return result
+	}
+	switch typ.Kind() {
+	case reflect.Slice:
+		result = append(result, reflect.MakeSlice(typ, 0, 0))
+		for _, e := range edgeValues(typ.Elem(), depth-1) {
+			result = append(result, reflect.Append(reflect.MakeSlice(typ, 0, 1), e))
+		}
+	case reflect.Map:
+		result = append(result, reflect.MakeMap(typ))
+	case reflect.Ptr:
+		for _, e := range edgeValues(typ.Elem(), depth-1) {
+			p := reflect.New(typ.Elem())
+			p.Elem().Set(e)
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package testutils
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCheckPanicFreeForAllInputs(t *testing.T) {
+	CheckPanicFreeForAllInputs([]string{"a"}, func(s string) { _ = strings.ToUpper(s) }, t)
+	CheckPanicFreeForAllInputs(nil, func(p *int) {
+		if p != nil {
+			_ = *p + 1
+		}
+	}, t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPanicFreeForAllInputs(nil, func(p *int) { _ = *p }, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPanicFreeForAllInputs([]int{1}, func(i int) { _ = 10 / i }, ft)
+	})
+
+	m := &messageTB{}
+	CheckPanicFreeForAllInputs([]string{"ab"}, func(s string) { _ = s[1] }, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected no panics, got 3:\n  "": runtime error: index out of range`, m.messages[0], t)
+}
+
+func TestEdgeValues(t *testing.T) {
+	CheckEqual([]int8{0, 1, -1, math.MinInt8, math.MaxInt8}, EdgeValues[int8](), t)
+	CheckEqual([]uint16{0, 1, math.MaxUint16}, EdgeValues[uint16](), t)
+	CheckEqual([]bool{false, true}, EdgeValues[bool](), t)
+
+	floats := EdgeValues[float32]()
+	CheckTrue(math.IsNaN(float64(floats[len(floats)-1])), t)
+	CheckTrue(math.Signbit(float64(floats[1])), t)
+
+	strs := EdgeValues[string]()
+	CheckFalse(utf8.ValidString(strs[3]), t)
+
+	ptrs := EdgeValues[*bool]()
+	CheckEqual(3, len(ptrs), t)
+	CheckNil(ptrs[0], t)
+	CheckTrue(*ptrs[2], t)
+
+	slices := EdgeValues[[]uint8]()
+	CheckNil(slices[0], t)
+	CheckEqual(0, len(slices[1]), t)
+	CheckEqual([]uint8{255}, slices[4], t)
+
+	type s struct{ A int }
+	CheckEqual([]s{{}}, EdgeValues[s](), t)
+	CheckEqual(2, len(EdgeValues[map[string]int]()), t)
+	CheckEqual(1, len(EdgeValues[error]()), t)
+}
+
+func TestCheckPanicFreeForAllInputs_longInput(t *testing.T) {
+	m := &messageTB{}
+	CheckPanicFreeForAllInputs(nil, func(s string) {
+		if len(s) > 100 {
+			panic("too long")
+		}
+	}, m)
+	CheckEqual([]string{"Expected no panics, got 1:\n  \"" + strings.Repeat("x", 79) + "...: too long"}, m.messages, t)
+}
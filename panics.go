@@ -3,7 +3,7 @@ package testutils
 import "testing"
 
 // ShouldNotPanic is used to assert that a function does not panic
-func ShouldNotPanic(t *testing.T) {
+func ShouldNotPanic(t testing.TB) {
 	t.Helper()
 	if r := recover(); r != nil {
 		t.Error("Unexpected panic")
@@ -11,7 +11,7 @@ func ShouldNotPanic(t *testing.T) {
 }
 
 // ShouldPanic is used to assert that a function does panic
-func ShouldPanic(t *testing.T) {
+func ShouldPanic(t testing.TB) {
 	t.Helper()
 	if r := recover(); r == nil {
 		t.Error("Expected panic")
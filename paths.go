@@ -0,0 +1,65 @@
+package testutils
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// CheckPathListEqual checks that the got path list (such as the value of PATH, GOPATH, or LD_LIBRARY_PATH) has the
+// same entries in the same order as the expected list. Both lists are split with the separator of the current
+// OS (':' or ';'). On failure a diff of the entries is reported.
+func CheckPathListEqual(expected, got string, t testing.TB) {
+	if diff, ok := produceDiff(filepath.SplitList(expected), filepath.SplitList(got), envDiffLimit()); !ok {
+		t.Helper()
+		t.Fatalf("Expected path list %q, got %q - see diff:\n%s", expected, got, diff)
+	}
+}
+
+// CheckPathListElementsEqual checks that the got path list has the same entries as the expected list, irrespective
+// of their order. Both lists are split with the separator of the current OS (':' or ';'). On failure the missing
+// and extra entries are reported.
+func CheckPathListElementsEqual(expected, got string, t testing.TB) {
+	counts := map[string]int{}
+	for _, e := range filepath.SplitList(got) {
+		counts[e]++
+	}
+	var missing, extra []string
+	for _, e := range filepath.SplitList(expected) {
+		if counts[e] > 0 {
+			counts[e]--
+		} else {
+			missing = append(missing, e)
+		}
+	}
+	for _, e := range filepath.SplitList(got) {
+		if counts[e] > 0 {
+			counts[e]--
+			extra = append(extra, e)
+		}
+	}
+	if len(missing) > 0 || len(extra) > 0 {
+		t.Helper()
+		t.Fatalf("Expected path list %q to have the entries of %q, missing: [%s], extra: [%s]",
+			got, expected, strings.Join(missing, ", "), strings.Join(extra, ", "))
+	}
+}
+
+// caseInsensitiveFS is true when the default file system of the OS compares names without regard to case. It is a
+// variable to allow it to be overridden.
+var caseInsensitiveFS = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+
+// CheckOSPathEqual checks that the got file path is equal to the expected path after both have been normalized:
+// '/' is converted to the separator of the current OS, redundant separators and `.` and `..` segments are removed
+// as by filepath.Clean, and on OSes with case-insensitive file systems (Windows and macOS) the case is ignored.
+// The expected path can therefore be written with '/' irrespective of OS.
+func CheckOSPathEqual(expected, got string, t testing.TB) {
+	e := filepath.Clean(filepath.FromSlash(expected))
+	g := filepath.Clean(filepath.FromSlash(got))
+	if e == g || caseInsensitiveFS && strings.EqualFold(e, g) {
+		return
+	}
+	t.Helper()
+	t.Fatalf("Expected path %q (%q), got %q (%q)", expected, e, got, g)
+}
@@ -0,0 +1,53 @@
+package testutils
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func pathList(entries ...string) string {
+	return strings.Join(entries, string(filepath.ListSeparator))
+}
+
+func TestCheckPathListEqual(t *testing.T) {
+	CheckPathListEqual(pathList("/bin", "/usr/bin"), pathList("/bin", "/usr/bin"), t)
+	CheckPathListEqual("", "", t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPathListEqual(pathList("/bin", "/usr/bin"), pathList("/usr/bin", "/bin"), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPathListEqual(pathList("/bin", "/usr/bin"), pathList("/bin"), ft)
+	})
+}
+
+func TestCheckPathListElementsEqual(t *testing.T) {
+	CheckPathListElementsEqual(pathList("/bin", "/usr/bin"), pathList("/usr/bin", "/bin"), t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPathListElementsEqual(pathList("/bin", "/usr/bin"), pathList("/bin", "/bin"), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckPathListElementsEqual(pathList("/bin"), pathList("/bin", "/opt/bin"), ft)
+	})
+}
+
+func TestCheckOSPathEqual(t *testing.T) {
+	CheckOSPathEqual("a/b/c", filepath.Join("a", "b", "c"), t)
+	CheckOSPathEqual("a/b/c", filepath.Join("a", ".", "x", "..", "b", "c")+string(filepath.Separator), t)
+	CheckOSPathEqual("/a//b", filepath.FromSlash("/a/b"), t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckOSPathEqual("a/b", filepath.Join("a", "c"), ft)
+	})
+}
+
+func TestCheckOSPathEqual_case(t *testing.T) {
+	saved := caseInsensitiveFS
+	defer func() { caseInsensitiveFS = saved }()
+
+	caseInsensitiveFS = true
+	CheckOSPathEqual("a/B", filepath.Join("A", "b"), t)
+	caseInsensitiveFS = false
+	ensureFailed(t, func(ft *testing.T) {
+		CheckOSPathEqual("a/B", filepath.Join("A", "b"), ft)
+	})
+}
@@ -0,0 +1,94 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+// PollOptions are the options of CheckEventuallyWith, CheckConsistentlyWith, and CheckNeverWith
+type PollOptions struct {
+	// Interval is the time between calls to the condition, 10ms if not set
+	Interval time.Duration
+
+	// Clock is the clock used to measure and wait for time, SystemClock if not set. With a FakeClock, the check
+	// advances the clock by the interval between calls instead of waiting, which makes the check instant and
+	// deterministic when the code under test uses the same clock.
+	Clock Clock
+}
+
+// CheckEventually checks that the condition becomes true within the given timeout by calling it every interval,
+// starting immediately. It returns as soon as the condition is true.
+func CheckEventually(cond func() bool, timeout, interval time.Duration, t testing.TB) {
+	t.Helper()
+	CheckEventuallyWith(cond, timeout, PollOptions{Interval: interval}, t)
+}
+
+// CheckEventuallyWith is CheckEventually with options
+func CheckEventuallyWith(cond func() bool, timeout time.Duration, opts PollOptions, t testing.TB) {
+	if elapsed, ok := poll(cond, true, timeout, opts); !ok {
+		t.Helper()
+		t.Fatalf("Expected condition to become true within %v, but it was still false after %v", timeout, elapsed)
+	}
+}
+
+// CheckConsistently checks that the condition holds for the full duration by calling it every interval, starting
+// immediately. This is useful to verify that something asynchronous keeps a state, e.g. that a connection stays
+// open. On failure the time at which the condition became false is reported.
+func CheckConsistently(cond func() bool, duration, interval time.Duration, t testing.TB) {
+	t.Helper()
+	CheckConsistentlyWith(cond, duration, PollOptions{Interval: interval}, t)
+}
+
+// CheckConsistentlyWith is CheckConsistently with options
+func CheckConsistentlyWith(cond func() bool, duration time.Duration, opts PollOptions, t testing.TB) {
+	if at, ok := poll(cond, false, duration, opts); ok {
+		t.Helper()
+		t.Fatalf("Expected condition to hold for %v, but it was false after %v", duration, at)
+	}
+}
+
+// CheckNever checks that the condition never becomes true during the full duration by calling it every interval,
+// starting immediately. This is useful to verify the absence of an asynchronous side effect, e.g. that no message
+// is sent. On failure the time at which the condition became true is reported.
+func CheckNever(cond func() bool, duration, interval time.Duration, t testing.TB) {
+	t.Helper()
+	CheckNeverWith(cond, duration, PollOptions{Interval: interval}, t)
+}
+
+// CheckNeverWith is CheckNever with options
+func CheckNeverWith(cond func() bool, duration time.Duration, opts PollOptions, t testing.TB) {
+	if at, ok := poll(cond, true, duration, opts); ok {
+		t.Helper()
+		t.Fatalf("Expected condition to never be true during %v, but it was true after %v", duration, at)
+	}
+}
+
+// poll calls cond every interval until it returns want or the duration has passed, with a last call at the end of
+// the duration. It returns the elapsed time, and true if cond returned want.
+func poll(cond func() bool, want bool, duration time.Duration, opts PollOptions) (time.Duration, bool) {
+	clock := opts.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = 10 * time.Millisecond
+	}
+	start := clock.Now()
+	for {
+		ok := cond() == want
+		elapsed := clock.Now().Sub(start)
+		if ok || elapsed >= duration {
+			return elapsed, ok
+		}
+		wait := interval
+		if remaining := duration - elapsed; remaining < wait {
+			wait = remaining
+		}
+		if fc, isFake := clock.(*FakeClock); isFake {
+			fc.Advance(wait)
+		} else {
+			clock.Sleep(wait)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package testutils
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckEventually(t *testing.T) {
+	var flag int32
+	time.AfterFunc(5*time.Millisecond, func() { atomic.StoreInt32(&flag, 1) })
+	CheckEventually(func() bool { return atomic.LoadInt32(&flag) == 1 }, time.Second, time.Millisecond, t)
+
+	m := &messageTB{}
+	CheckEventually(func() bool { return false }, 10*time.Millisecond, time.Millisecond, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected condition to become true within 10ms, but it was still false after `, m.messages[0], t)
+}
+
+func TestCheckConsistently(t *testing.T) {
+	CheckConsistently(func() bool { return true }, 10*time.Millisecond, time.Millisecond, t)
+
+	var flag int32
+	time.AfterFunc(5*time.Millisecond, func() { atomic.StoreInt32(&flag, 1) })
+	m := &messageTB{}
+	CheckConsistently(func() bool { return atomic.LoadInt32(&flag) == 0 }, time.Second, time.Millisecond, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected condition to hold for 1s, but it was false after `, m.messages[0], t)
+}
+
+func TestCheckNever(t *testing.T) {
+	calls := 0
+	CheckNever(func() bool { calls++; return false }, 10*time.Millisecond, time.Millisecond, t)
+	CheckTrue(calls > 1, t)
+
+	m := &messageTB{}
+	CheckNever(func() bool { return true }, time.Second, time.Millisecond, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected condition to never be true during 1s, but it was true after `, m.messages[0], t)
+}
+
+func TestPollWithFakeClock(t *testing.T) {
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	deadline := clock.Now().Add(time.Hour)
+	expired := func() bool { return !clock.Now().Before(deadline) }
+	opts := PollOptions{Interval: time.Minute, Clock: clock}
+
+	CheckEventuallyWith(expired, 2*time.Hour, opts, t)
+	CheckEqual(deadline, clock.Now(), t)
+
+	clock = NewFakeClock(deadline.Add(-time.Hour))
+	opts.Clock = clock
+	CheckNeverWith(expired, 59*time.Minute, opts, t)
+	CheckConsistentlyWith(func() bool { return !expired() }, 30*time.Second, opts, t)
+
+	m := &messageTB{}
+	CheckConsistentlyWith(func() bool { return !expired() }, 2*time.Hour, opts, m)
+	CheckEventuallyWith(func() bool { return false }, 90*time.Second, opts, m)
+	CheckEqual([]string{
+		"Expected condition to hold for 2h0m0s, but it was false after 1m0s",
+		"Expected condition to become true within 1m30s, but it was still false after 1m30s",
+	}, m.messages, t)
+}
@@ -0,0 +1,5 @@
+module github.com/hlindberg/testutils/protocheck
+
+go 1.20
+
+require google.golang.org/protobuf v1.34.2
@@ -0,0 +1,112 @@
+// Package protocheck contains checks for protocol buffer messages. It is a separate module to make the
+// github.com/hlindberg/testutils module usable without a dependency on google.golang.org/protobuf.
+package protocheck
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// CheckProtoEqual checks that the two messages are equal as determined by proto.Equal, and calls t.Fatalf if not.
+// Generated message structs contain internal state which makes reflect.DeepEqual (and therefore
+// testutils.CheckEqual) unsuitable for comparing them. On failure the path to the first differing field is
+// reported together with the text form of both messages.
+func CheckProtoEqual(expected, got proto.Message, t testing.TB) {
+	if proto.Equal(expected, got) {
+		return
+	}
+	t.Helper()
+	path := "message"
+	if expected != nil && got != nil {
+		if p, differs := diffMessage(expected.ProtoReflect(), got.ProtoReflect(), string(expected.ProtoReflect().Descriptor().Name())); differs {
+			path = p
+		}
+	}
+	t.Fatalf("Expected equal messages, but %s differs:\nexpected: %s\ngot: %s", path, messageText(expected), messageText(got))
+}
+
+func messageText(m proto.Message) string {
+	if m == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%T{%s}", m, prototext.MarshalOptions{}.Format(m))
+}
+
+// diffMessage returns the path to the first field that differs between the two messages, and true if there is a
+// difference
+func diffMessage(a, b protoreflect.Message, path string) (string, bool) {
+	if a.Descriptor().FullName() != b.Descriptor().FullName() || a.IsValid() != b.IsValid() {
+		return path, true
+	}
+	fields := a.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		fp := path + "." + string(fd.Name())
+		if a.Has(fd) != b.Has(fd) {
+			return fp, true
+		}
+		if !a.Has(fd) {
+			continue
+		}
+		av, bv := a.Get(fd), b.Get(fd)
+		switch {
+		case fd.IsList():
+			al, bl := av.List(), bv.List()
+			if al.Len() != bl.Len() {
+				return fp, true
+			}
+			for j := 0; j < al.Len(); j++ {
+				if p, differs := diffValue(fd, al.Get(j), bl.Get(j), fmt.Sprintf("%s[%d]", fp, j)); differs {
+					return p, true
+				}
+			}
+		case fd.IsMap():
+			am, bm := av.Map(), bv.Map()
+			if am.Len() != bm.Len() {
+				return fp, true
+			}
+			var p string
+			var differs bool
+			am.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+				kp := fmt.Sprintf("%s[%v]", fp, k.Interface())
+				if !bm.Has(k) {
+					p, differs = kp, true
+				} else {
+					p, differs = diffValue(fd.MapValue(), v, bm.Get(k), kp)
+				}
+				return !differs
+			})
+			if differs {
+				return p, true
+			}
+		default:
+			if p, differs := diffValue(fd, av, bv, fp); differs {
+				return p, true
+			}
+		}
+	}
+	if !bytes.Equal(a.GetUnknown(), b.GetUnknown()) {
+		return path + ".<unknown fields>", true
+	}
+	return "", false
+}
+
+// diffValue compares two singular values of the given field
+func diffValue(fd protoreflect.FieldDescriptor, a, b protoreflect.Value, path string) (string, bool) {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return diffMessage(a.Message(), b.Message(), path)
+	case protoreflect.BytesKind:
+		return path, !bytes.Equal(a.Bytes(), b.Bytes())
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		af, bf := a.Float(), b.Float()
+		return path, af != bf && !(math.IsNaN(af) && math.IsNaN(bf))
+	}
+	return path, a.Interface() != b.Interface()
+}
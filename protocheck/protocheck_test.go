@@ -0,0 +1,83 @@
+package protocheck
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// checkEqual is a minimal CheckEqual, since this module does not depend on github.com/hlindberg/testutils
+func checkEqual(expected, got interface{}, t *testing.T) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected Equal: %T %v, got %T %v", expected, expected, got, got)
+	}
+}
+
+// messageTB records the messages of Fatalf
+type messageTB struct {
+	testing.TB
+	messages []string
+}
+
+func (m *messageTB) Helper() {}
+
+func (m *messageTB) Fatalf(format string, args ...interface{}) {
+	m.messages = append(m.messages, format)
+}
+
+func testFile() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name: proto.String("a.proto"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("A")},
+			{Name: proto.String("B"), Field: []*descriptorpb.FieldDescriptorProto{{Name: proto.String("x")}}},
+		},
+	}
+}
+
+func diffPath(expected, got proto.Message) string {
+	p, _ := diffMessage(expected.ProtoReflect(), got.ProtoReflect(), "root")
+	return p
+}
+
+func TestCheckProtoEqual(t *testing.T) {
+	CheckProtoEqual(testFile(), testFile(), t)
+	CheckProtoEqual(nil, nil, t)
+
+	m := &messageTB{}
+	changed := testFile()
+	changed.MessageType[1].Field[0].Name = proto.String("y")
+	CheckProtoEqual(testFile(), changed, m)
+	CheckProtoEqual(testFile(), nil, m)
+	CheckProtoEqual(durationpb.New(1), timestamppb.New(timestamppb.Now().AsTime()), m)
+	checkEqual(3, len(m.messages), t)
+}
+
+func Test_diffMessage(t *testing.T) {
+	changed := testFile()
+	changed.MessageType[1].Field[0].Name = proto.String("y")
+	checkEqual("root.message_type[1].field[0].name", diffPath(testFile(), changed), t)
+
+	changed = testFile()
+	changed.Package = proto.String("p")
+	checkEqual("root.package", diffPath(testFile(), changed), t)
+
+	changed = testFile()
+	changed.MessageType = changed.MessageType[:1]
+	checkEqual("root.message_type", diffPath(testFile(), changed), t)
+
+	a, _ := structpb.NewStruct(map[string]interface{}{"k": 1, "l": []interface{}{"x", 2.5}})
+	b, _ := structpb.NewStruct(map[string]interface{}{"k": 1, "l": []interface{}{"x", 3.5}})
+	checkEqual("root.fields[l].list_value.values[1].number_value", diffPath(a, b), t)
+	b, _ = structpb.NewStruct(map[string]interface{}{"k": 1, "m": []interface{}{"x", 2.5}})
+	checkEqual("root.fields[l]", diffPath(a, b), t)
+
+	_, differs := diffMessage(a.ProtoReflect(), a.ProtoReflect(), "root")
+	checkEqual(false, differs, t)
+}
@@ -0,0 +1,67 @@
+package testutils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// CheckRateLimited calls the function call n times as fast as possible and checks that no more than k calls
+// succeeded within any window of the given duration. The function should return true when the call was
+// allowed by the rate limiter under test, and false when it was rejected. A call that is delayed by the
+// limiter is considered to have succeeded at the time it returned.
+//
+// On failure the observed timeline of all calls is included in the error message.
+func CheckRateLimited(n, k int, window time.Duration, call func() bool, t testing.TB) {
+	t.Helper()
+	CheckRateLimitedWith(n, k, window, call, ClockOptions{}, t)
+}
+
+// CheckRateLimitedWith is CheckRateLimited with options. The time of each call is read from the clock of the
+// options, so a rate limiter that uses a FakeClock can be checked by letting call advance the same clock.
+func CheckRateLimitedWith(n, k int, window time.Duration, call func() bool, opts ClockOptions, t testing.TB) {
+	clock := opts.clock()
+	start := clock.Now()
+	timeline := make([]rateCall, n)
+	for i := range timeline {
+		ok := call()
+		timeline[i] = rateCall{at: clock.Now().Sub(start), ok: ok}
+	}
+
+	var allowed []time.Duration
+	for _, c := range timeline {
+		if c.ok {
+			allowed = append(allowed, c.at)
+		}
+	}
+	lo := 0
+	for hi, at := range allowed {
+		for at-allowed[lo] >= window {
+			lo++
+		}
+		if hi-lo+1 > k {
+			t.Helper()
+			t.Fatalf("Expected at most %d allowed calls within %v, got %d between %v and %v. Timeline:\n%s",
+				k, window, hi-lo+1, allowed[lo], at, formatRateTimeline(timeline))
+			return
+		}
+	}
+}
+
+type rateCall struct {
+	at time.Duration
+	ok bool
+}
+
+func formatRateTimeline(timeline []rateCall) string {
+	lines := make([]string, len(timeline))
+	for i, c := range timeline {
+		result := "rejected"
+		if c.ok {
+			result = "allowed"
+		}
+		lines[i] = fmt.Sprintf("  [%d] %12v %s", i, c.at, result)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,53 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckRateLimited(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		count := 0
+		CheckRateLimited(10, 3, time.Hour, func() bool {
+			count++
+			return count <= 3
+		}, ft)
+	})
+
+	ensureFailed(t, func(ft *testing.T) {
+		CheckRateLimited(10, 3, time.Hour, func() bool { return true }, ft)
+	})
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckRateLimited(3, 1, time.Millisecond, func() bool {
+			time.Sleep(2 * time.Millisecond)
+			return true
+		}, ft)
+	})
+}
+
+func TestCheckRateLimitedWith(t *testing.T) {
+	clock := NewFakeClock(clockStart)
+	var allowedAt []time.Time
+	call := func() bool {
+		// a limiter that allows 2 calls per second, called every 100ms
+		clock.Advance(100 * time.Millisecond)
+		now := clock.Now()
+		for len(allowedAt) > 0 && now.Sub(allowedAt[0]) >= time.Second {
+			allowedAt = allowedAt[1:]
+		}
+		if len(allowedAt) >= 2 {
+			return false
+		}
+		allowedAt = append(allowedAt, now)
+		return true
+	}
+	CheckRateLimitedWith(30, 2, time.Second, call, ClockOptions{Clock: clock}, t)
+
+	allowedAt = nil
+	m := &messageTB{}
+	CheckRateLimitedWith(30, 1, time.Second, call, ClockOptions{Clock: clock}, m)
+	CheckEqual(1, len(m.messages), t)
+	CheckMatches(`^Expected at most 1 allowed calls within 1s, got 2 between 100ms and 200ms. Timeline:\n +\[0\] +100ms allowed\n +\[1\] +200ms allowed\n +\[2\] +300ms rejected\n`,
+		m.messages[0], t)
+}
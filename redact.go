@@ -0,0 +1,48 @@
+package testutils
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Redact returns a Normalizer that replaces all matches of the given regular expression with the replacement,
+// which may refer to submatches as in regexp.ReplaceAllString. It panics if the expression is not valid.
+func Redact(pattern, replacement string) Normalizer {
+	rx := regexp.MustCompile(pattern)
+	return func(s string) string {
+		return rx.ReplaceAllString(s, replacement)
+	}
+}
+
+// RedactUUIDs replaces UUIDs with <UUID>
+var RedactUUIDs = Redact(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`, "<UUID>")
+
+// RedactTimestamps replaces RFC 3339 timestamps (with optional fractional seconds) with <TIMESTAMP>
+var RedactTimestamps = Redact(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:\.\d+)?(?:Z|[+-]\d{2}:\d{2})?`, "<TIMESTAMP>")
+
+// RedactPorts replaces the port of local addresses such as localhost:8080, 127.0.0.1:8080, and [::1]:8080 with
+// <PORT>
+var RedactPorts = Redact(`(\blocalhost|\b127\.0\.0\.1|\[::1\]|\[::\]|\b0\.0\.0\.0):\d+\b`, "$1:<PORT>")
+
+var redactions = struct {
+	sync.Mutex
+	normalizers []Normalizer
+}{}
+
+// RegisterRedactions registers normalizers that are applied to the got text by CheckGolden (and therefore by Snap
+// and the other golden file checks) before it is compared with or written to a golden file. This makes it possible
+// to keep nondeterministic values such as timestamps, UUIDs, and port numbers out of golden files. Redactions are
+// typically registered in TestMain or in an init function of a test file.
+func RegisterRedactions(normalizers ...Normalizer) {
+	redactions.Lock()
+	redactions.normalizers = append(redactions.normalizers, normalizers...)
+	redactions.Unlock()
+}
+
+// redact returns the text after applying all registered redactions to it
+func redact(s string) string {
+	redactions.Lock()
+	normalizers := redactions.normalizers
+	redactions.Unlock()
+	return NormalizeText(s, normalizers...)
+}
@@ -0,0 +1,39 @@
+package testutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	CheckEqual("id=<ID> id=<ID>", Redact(`\d+`, "<ID>")("id=12 id=3"), t)
+	CheckEqual("user <UUID> done", RedactUUIDs("user 123e4567-e89b-12d3-a456-426614174000 done"), t)
+	CheckEqual("at <TIMESTAMP>, <TIMESTAMP> and <TIMESTAMP>",
+		RedactTimestamps("at 2024-01-02T03:04:05Z, 2024-01-02 03:04:05.123+02:00 and 2024-01-02T03:04:05"), t)
+	CheckEqual("http://localhost:<PORT>/x [::1]:<PORT> example.com:80",
+		RedactPorts("http://localhost:51234/x [::1]:8080 example.com:80"), t)
+}
+
+func TestRegisterRedactions(t *testing.T) {
+	redactions.Lock()
+	saved := redactions.normalizers
+	redactions.normalizers = nil
+	redactions.Unlock()
+	defer func() {
+		redactions.Lock()
+		redactions.normalizers = saved
+		redactions.Unlock()
+	}()
+
+	RegisterRedactions(RedactUUIDs, RedactPorts)
+	CheckEqual("<UUID> localhost:<PORT>", redact("123e4567-e89b-12d3-a456-426614174000 localhost:1"), t)
+
+	golden := filepath.Join(t.TempDir(), "redacted.golden")
+	if err := os.WriteFile(golden, []byte("listening on localhost:<PORT>\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !UpdateGolden() {
+		CheckGolden(golden, "listening on localhost:43210\n", t)
+	}
+}
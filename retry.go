@@ -0,0 +1,88 @@
+package testutils
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// CountingHook is a hook to inject into a retrying operation to count its attempts and to make attempts fail. Its
+// Call method is called by each attempt (typically from a fake of the dependency being retried), and its Sleep
+// method is injected as the function the operation uses to wait between attempts, which makes backoff delays
+// take no time while they are recorded.
+type CountingHook struct {
+	lock     sync.Mutex
+	failures int
+	err      error
+	calls    int
+	delays   []time.Duration
+	clock    *FakeClock
+}
+
+// NewCountingHook returns a CountingHook that makes the first failures calls to Call return the given error
+func NewCountingHook(failures int, err error) *CountingHook {
+	return &CountingHook{failures: failures, err: err}
+}
+
+// Call counts the call and returns the injected error if the number of calls does not exceed the number of
+// failures, and nil otherwise
+func (h *CountingHook) Call() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.calls++
+	if h.calls <= h.failures {
+		return h.err
+	}
+	return nil
+}
+
+// Calls returns the number of calls made to Call
+func (h *CountingHook) Calls() int {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.calls
+}
+
+// WithClock makes Sleep advance the given fake clock by the delay, so that code that reads the same clock sees
+// the time pass. It returns the hook for convenient chaining with NewCountingHook.
+func (h *CountingHook) WithClock(clock *FakeClock) *CountingHook {
+	h.lock.Lock()
+	h.clock = clock
+	h.lock.Unlock()
+	return h
+}
+
+// Sleep records the delay without sleeping, and advances the clock set with WithClock (if any) by the delay
+func (h *CountingHook) Sleep(d time.Duration) {
+	h.lock.Lock()
+	h.delays = append(h.delays, d)
+	clock := h.clock
+	h.lock.Unlock()
+	if clock != nil {
+		clock.Advance(d)
+	}
+}
+
+// Delays returns the delays given to Sleep
+func (h *CountingHook) Delays() []time.Duration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]time.Duration{}, h.delays...)
+}
+
+// CheckCalledTimes checks that Call of the hook was called exactly n times. On failure the recorded delays are
+// included in the message.
+func CheckCalledTimes(h *CountingHook, n int, t testing.TB) {
+	if calls := h.Calls(); calls != n {
+		t.Helper()
+		t.Fatalf("Expected %d attempts, got %d (delays between attempts: %s)", n, calls, formatDelays(h.Delays()))
+	}
+}
+
+func formatDelays(delays []time.Duration) string {
+	if len(delays) == 0 {
+		return "none"
+	}
+	return fmt.Sprint(delays)
+}
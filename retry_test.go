@@ -0,0 +1,52 @@
+package testutils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// retry calls op up to attempts times with exponential backoff starting at one second
+func retry(attempts int, sleep func(time.Duration), op func() error) error {
+	delay := time.Second
+	var err error
+	for i := 0; i < attempts; i++ {
+		if err = op(); err == nil {
+			return nil
+		}
+		if i < attempts-1 {
+			sleep(delay)
+			delay *= 2
+		}
+	}
+	return err
+}
+
+func TestCheckCalledTimes(t *testing.T) {
+	h := NewCountingHook(2, errUnavailable)
+	CheckNotError(retry(5, h.Sleep, h.Call), t)
+	CheckCalledTimes(h, 3, t)
+	CheckEqual([]time.Duration{time.Second, 2 * time.Second}, h.Delays(), t)
+
+	h = NewCountingHook(10, errUnavailable)
+	CheckEqual(errUnavailable, retry(3, h.Sleep, h.Call), t)
+	CheckCalledTimes(h, 3, t)
+
+	m := &messageTB{}
+	CheckCalledTimes(h, 4, m)
+	CheckCalledTimes(NewCountingHook(0, nil), 1, m)
+	CheckEqual([]string{
+		"Expected 4 attempts, got 3 (delays between attempts: [1s 2s])",
+		"Expected 1 attempts, got 0 (delays between attempts: none)",
+	}, m.messages, t)
+}
+
+func TestCountingHook_WithClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	h := NewCountingHook(3, errUnavailable).WithClock(clock)
+	CheckNotError(retry(5, h.Sleep, h.Call), t)
+	CheckEqual(start.Add(7*time.Second), clock.Now(), t)
+}
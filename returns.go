@@ -0,0 +1,96 @@
+package testutils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// Returns returns its arguments as a slice. It makes it possible to pass all return values of a function call
+// to CheckReturns, i.e. CheckReturns([]interface{}{1, "a", nil}, Returns(f()), t).
+func Returns(values ...interface{}) []interface{} {
+	return values
+}
+
+// CheckReturns checks that each of the got return values is equal to the expected value at the same position
+// as by CheckEqual. An expected error matches a got error when errors.Is returns true. On failure every position
+// that differs is reported.
+func CheckReturns(expected, got []interface{}, t testing.TB) {
+	if len(expected) != len(got) {
+		t.Helper()
+		t.Fatalf("Expected %d return values, got %d", len(expected), len(got))
+		return
+	}
+	var diffs []string
+	for i, e := range expected {
+		g := got[i]
+		if ee, ok := e.(error); ok {
+			if ge, ok := g.(error); ok && errors.Is(ge, ee) {
+				continue
+			}
+		} else if valuesEqual(e, g) {
+			continue
+		}
+		diffs = append(diffs, fmt.Sprintf("  [%d]: expected %T %v, got %T %v", i, e, e, g, g))
+	}
+	if len(diffs) > 0 {
+		t.Helper()
+		t.Fatalf("Expected return values %v, got %v:\n%s", expected, got, strings.Join(diffs, "\n"))
+	}
+}
+
+// CheckResult checks the result of a function returning a (value, error) pair, such as in a table driven test
+// where each case either expects a value or an error. The expectedError is an error that must match the got error
+// as determined by errors.Is, or a string or *regexp.Regexp that must match the message of the got error. When
+// expectedError is nil (or zero, such as an empty string), no error is expected and gotValue must be equal to
+// expectedValue as determined by CheckEqual. When an error is expected, expectedValue must be nil or a zero value,
+// which makes it possible to pass typed fields of a test case, such as `want int` and `wantErr string`, as is.
+func CheckResult(expectedValue, expectedError, gotValue interface{}, gotError error, t testing.TB) {
+	t.Helper()
+	if isZeroValue(expectedError) {
+		if gotError != nil {
+			t.Fatalf("Expected value %T %v, got error %q", expectedValue, expectedValue, gotError.Error())
+			return
+		}
+		if !valuesEqual(expectedValue, gotValue) {
+			unequalValues(expectedValue, gotValue, t)
+		}
+		return
+	}
+	if !isZeroValue(expectedValue) {
+		t.Fatalf("CheckResult: both a value %v and an error %v are expected", expectedValue, expectedError)
+		return
+	}
+	if gotError == nil {
+		t.Fatalf("Expected error matching %v, got value %T %v", expectedError, gotValue, gotValue)
+		return
+	}
+	var matches bool
+	switch e := expectedError.(type) {
+	case error:
+		matches = errors.Is(gotError, e)
+	case string:
+		rx, err := regexp.Compile(e)
+		if err != nil {
+			t.Fatalf("CheckResult: illegal regexp %q", e)
+			return
+		}
+		matches = rx.MatchString(gotError.Error())
+	case *regexp.Regexp:
+		matches = e.MatchString(gotError.Error())
+	default:
+		t.Fatalf("CheckResult: expected error must be an error, a string, or a regexp, got %T %v", expectedError, expectedError)
+		return
+	}
+	if !matches {
+		t.Fatalf("Expected error matching %v, got error %q", expectedError, gotError.Error())
+	}
+}
+
+// isZeroValue returns true if v is nil or the zero value of its type
+func isZeroValue(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
@@ -0,0 +1,88 @@
+package testutils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func threeReturns(fail bool) (int, string, error) {
+	if fail {
+		return 0, "", fmt.Errorf("reading: %w", io.EOF)
+	}
+	return 1, "a", nil
+}
+
+func TestCheckReturns(t *testing.T) {
+	CheckReturns([]interface{}{1, "a", nil}, Returns(threeReturns(false)), t)
+	CheckReturns([]interface{}{0, "", io.EOF}, Returns(threeReturns(true)), t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReturns([]interface{}{1, "a"}, Returns(threeReturns(false)), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReturns([]interface{}{1, "a", nil}, Returns(threeReturns(true)), ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckReturns([]interface{}{0, "", errors.New("other")}, Returns(threeReturns(true)), ft)
+	})
+
+	m := &messageTB{}
+	CheckReturns([]interface{}{2, "a", io.EOF}, Returns(threeReturns(false)), m)
+	CheckEqual([]string{"Expected return values [2 a EOF], got [1 a <nil>]:\n" +
+		"  [0]: expected int 2, got int 1\n  [2]: expected *errors.errorString EOF, got <nil> <nil>"}, m.messages, t)
+}
+
+func intResult(fail bool) (int, error) {
+	if fail {
+		return 0, fmt.Errorf("reading: %w", io.EOF)
+	}
+	return 1, nil
+}
+
+func TestCheckResult(t *testing.T) {
+	v, err := intResult(false)
+	CheckResult(1, nil, v, err, t)
+	ensureFailed(t, func(ft *testing.T) {
+		CheckResult(2, nil, v, err, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckResult(nil, io.EOF, v, err, ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckResult(1, io.EOF, v, err, ft)
+	})
+
+	v, err = intResult(true)
+	CheckResult(nil, io.EOF, v, err, t)
+	CheckResult(nil, "^reading", v, err, t)
+	CheckResult(nil, regexp.MustCompile("EOF$"), v, err, t)
+	for _, expectedError := range []interface{}{io.ErrUnexpectedEOF, "^writing", regexp.MustCompile("^EOF"), "[", 1} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckResult(nil, expectedError, v, err, ft)
+		})
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckResult(0, nil, v, err, ft)
+	})
+}
+
+func TestCheckResult_table(t *testing.T) {
+	type testCase struct {
+		Name    string
+		Fail    bool
+		Want    int
+		WantErr string
+	}
+	for _, c := range []testCase{
+		{Name: "ok", Want: 1},
+		{Name: "error", Fail: true, WantErr: "EOF$"},
+	} {
+		got, err := intResult(c.Fail)
+		CheckResult(c.Want, c.WantErr, got, err, t)
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckResult(1, "EOF$", 0, io.EOF, ft)
+	})
+}
@@ -0,0 +1,115 @@
+package testutils
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Scenario is a multi-step test, such as an integration test, built by chaining calls to Step. The steps run
+// in order as subtests, and share a state of type S that is available to them from State. A Scenario is created
+// with NewScenario and run with Run.
+//
+//	sc := testutils.NewScenario[orderState]()
+//	sc.Setup(func(tt testutils.Tester) { sc.State().db = openDB(tt) }).
+//		Step("create order", func(tt testutils.Tester) { sc.State().id = createOrder(tt, sc.State().db) }).
+//		Step("pay order", func(tt testutils.Tester) { tt.CheckNotError(pay(sc.State().db, sc.State().id)) }).
+//		Teardown(func(tt testutils.Tester) { sc.State().db.Close() }).
+//		Run(t)
+type Scenario[S any] struct {
+	state             S
+	setup             func(tt Tester)
+	teardown          func(tt Tester)
+	steps             []scenarioStep
+	continueOnFailure bool
+}
+
+type scenarioStep struct {
+	name string
+	f    func(tt Tester)
+}
+
+// NewScenario returns a new Scenario without steps, and with a zero state
+func NewScenario[S any]() *Scenario[S] {
+	return &Scenario[S]{}
+}
+
+// State returns the state shared by the setup, the steps, and the teardown of the scenario
+func (sc *Scenario[S]) State() *S {
+	return &sc.state
+}
+
+// Setup sets a function that runs as the subtest "setup" before the first step. No steps run if the setup fails.
+func (sc *Scenario[S]) Setup(f func(tt Tester)) *Scenario[S] {
+	sc.setup = f
+	return sc
+}
+
+// Teardown sets a function that runs as the subtest "teardown" after the last step. The teardown runs even if
+// the setup or a step failed.
+func (sc *Scenario[S]) Teardown(f func(tt Tester)) *Scenario[S] {
+	sc.teardown = f
+	return sc
+}
+
+// ContinueOnFailure makes the scenario run all steps even when a step fails. By default, the steps after a failed
+// step are skipped since they typically depend on the outcome of the earlier steps.
+func (sc *Scenario[S]) ContinueOnFailure() *Scenario[S] {
+	sc.continueOnFailure = true
+	return sc
+}
+
+// Step adds a step with the given name that runs f as a subtest
+func (sc *Scenario[S]) Step(name string, f func(tt Tester)) *Scenario[S] {
+	sc.steps = append(sc.steps, scenarioStep{name: name, f: f})
+	return sc
+}
+
+// Run runs the setup, the steps, and the teardown of the scenario in order as subtests of t, and returns true if
+// they all passed. The state is reset to its zero value before the setup runs. If something fails, a summary
+// with the outcome of each step is logged to t.
+func (sc *Scenario[S]) Run(t testing.TB) bool {
+	t.Helper()
+	return sc.run(NewTester(t), t.Log)
+}
+
+// run is Run with the subtests run by tt, and the summary logged by log
+func (sc *Scenario[S]) run(tt Tester, log func(args ...interface{})) bool {
+	var zero S
+	sc.state = zero
+	var outcomes []string
+	outcome := func(name, status string) {
+		outcomes = append(outcomes, fmt.Sprintf("  %-7s %s", status, name))
+	}
+	runPart := func(name string, f func(tt Tester)) bool {
+		ok := tt.Run(name, f)
+		if ok {
+			outcome(name, "ok")
+		} else {
+			outcome(name, "FAILED")
+		}
+		return ok
+	}
+
+	ok := true
+	if sc.setup != nil {
+		ok = runPart("setup", sc.setup)
+	}
+	failed := !ok
+	for _, s := range sc.steps {
+		if failed && (!ok || !sc.continueOnFailure) {
+			outcome(s.name, "skipped")
+			continue
+		}
+		if !runPart(s.name, s.f) {
+			failed = true
+		}
+	}
+	if sc.teardown != nil && !runPart("teardown", sc.teardown) {
+		failed = true
+	}
+	if failed {
+		log("Scenario summary:\n" + strings.Join(outcomes, "\n"))
+	}
+	return !failed
+}
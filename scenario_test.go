@@ -0,0 +1,76 @@
+package testutils
+
+import "testing"
+
+// recordingRunner is a Tester that runs subtests against a RecordingTB, so that failing steps do not fail the test
+type recordingRunner struct {
+	Tester
+	t        *testing.T
+	failures []string
+}
+
+func (rr *recordingRunner) Run(name string, f func(tt Tester)) bool {
+	r := NewRecordingTB(rr.t)
+	r.Run(func(tb testing.TB) { f(NewTester(tb)) })
+	for _, m := range r.Failures() {
+		rr.failures = append(rr.failures, name+": "+m)
+	}
+	return !r.Failed()
+}
+
+type scenarioState struct {
+	steps []string
+}
+
+func TestScenario_Run(t *testing.T) {
+	sc := NewScenario[scenarioState]()
+	sc.Setup(func(tt Tester) { sc.State().steps = append(sc.State().steps, "setup") }).
+		Step("first", func(tt Tester) { sc.State().steps = append(sc.State().steps, "first") }).
+		Step("second", func(tt Tester) { sc.State().steps = append(sc.State().steps, "second") }).
+		Teardown(func(tt Tester) { tt.CheckEqual([]string{"setup", "first", "second"}, sc.State().steps) })
+	CheckTrue(sc.Run(t), t)
+	CheckTrue(sc.Run(t), t)
+}
+
+func TestScenario_failure(t *testing.T) {
+	var ran []string
+	step := func(name string, fail bool) func(tt Tester) {
+		return func(tt Tester) {
+			ran = append(ran, name)
+			tt.CheckFalse(fail)
+		}
+	}
+	newScenario := func() *Scenario[int] {
+		return NewScenario[int]().
+			Step("a", step("a", false)).
+			Step("b", step("b", true)).
+			Step("c", step("c", false)).
+			Teardown(step("teardown", false))
+	}
+
+	rr := &recordingRunner{t: t}
+	var logged []interface{}
+	CheckFalse(newScenario().run(rr, func(args ...interface{}) { logged = args }), t)
+	CheckEqual([]string{"a", "b", "teardown"}, ran, t)
+	CheckEqual([]string{"b: Expected: false, got true"}, rr.failures, t)
+	CheckEqual([]interface{}{`Scenario summary:
+  ok      a
+  FAILED  b
+  skipped c
+  ok      teardown`}, logged, t)
+
+	ran = nil
+	CheckFalse(newScenario().ContinueOnFailure().run(rr, func(args ...interface{}) {}), t)
+	CheckEqual([]string{"a", "b", "c", "teardown"}, ran, t)
+
+	ran = nil
+	logged = nil
+	CheckFalse(newScenario().ContinueOnFailure().Setup(step("setup", true)).run(rr, func(args ...interface{}) { logged = args }), t)
+	CheckEqual([]string{"setup", "teardown"}, ran, t)
+	CheckEqual([]interface{}{`Scenario summary:
+  FAILED  setup
+  skipped a
+  skipped b
+  skipped c
+  ok      teardown`}, logged, t)
+}
@@ -0,0 +1,44 @@
+// Package selftest helps testing custom checks, such as checks written on top of github.com/hlindberg/testutils.
+// A check is tested by running it against a recording testing.TB and asserting that it failed (or did not fail).
+package selftest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hlindberg/testutils"
+)
+
+// EnsureFailed runs f as a subtest of t, and fails the subtest if f did not fail the testing.TB it is given. The
+// testing.TB given to f records failures instead of reporting them, and Fatal, Fatalf, FailNow, and Skip stop f
+// (but not the subtest). All other methods, such as TempDir, Setenv, and Cleanup, are those of the subtest.
+func EnsureFailed(t *testing.T, f func(tb testing.TB)) {
+	t.Helper()
+	t.Run("ensure failed", func(t *testing.T) {
+		t.Helper()
+		r := run(t, f)
+		if !r.Failed() {
+			t.Errorf("Expected a failure, got none")
+		}
+	})
+}
+
+// EnsureNotFailed runs f as a subtest of t, and fails the subtest if f failed the testing.TB it is given. The
+// messages of the failure are reported. See EnsureFailed.
+func EnsureNotFailed(t *testing.T, f func(tb testing.TB)) {
+	t.Helper()
+	t.Run("ensure not failed", func(t *testing.T) {
+		t.Helper()
+		r := run(t, f)
+		if r.Failed() {
+			t.Errorf("Expected no failure, got:\n%s", strings.Join(r.Failures(), "\n"))
+		}
+	})
+}
+
+// run calls f with a recording TB wrapping t and waits for it to finish
+func run(t *testing.T, f func(tb testing.TB)) *testutils.RecordingTB {
+	r := testutils.NewRecordingTB(t)
+	r.Run(f)
+	return r
+}
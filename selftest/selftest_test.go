@@ -0,0 +1,53 @@
+package selftest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hlindberg/testutils"
+)
+
+func checkPositive(n int, tb testing.TB) {
+	tb.Helper()
+	if n <= 0 {
+		tb.Fatalf("Expected a positive number, got %d", n)
+	}
+}
+
+func TestEnsureFailed(t *testing.T) {
+	EnsureFailed(t, func(tb testing.TB) {
+		checkPositive(0, tb)
+	})
+	EnsureFailed(t, func(tb testing.TB) {
+		testutils.CheckEqual(1, 2, tb)
+		t.Error("Fatalf did not stop the function")
+	})
+	EnsureFailed(t, func(tb testing.TB) {
+		tb.Error("error")
+		tb.Fail()
+	})
+}
+
+func TestEnsureNotFailed(t *testing.T) {
+	EnsureNotFailed(t, func(tb testing.TB) {
+		checkPositive(1, tb)
+		testutils.CheckEqual(1, 1, tb)
+	})
+	EnsureNotFailed(t, func(tb testing.TB) {
+		tb.Skip("skipped")
+	})
+	EnsureNotFailed(t, func(tb testing.TB) {
+		dir := tb.TempDir()
+		testutils.CheckNotError(os.WriteFile(dir+"/x", []byte("x"), 0o644), tb)
+	})
+}
+
+func Test_run(t *testing.T) {
+	r := run(t, func(tb testing.TB) {
+		tb.Errorf("a %d", 1)
+		tb.Error("b", 2)
+		tb.FailNow()
+	})
+	testutils.CheckTrue(r.Failed(), t)
+	testutils.CheckEqual([]string{"a 1", "b 2"}, r.Failures(), t)
+}
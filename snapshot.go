@@ -0,0 +1,162 @@
+package testutils
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// snapshotDir is the directory where Snap stores snapshots. It is a variable to allow it to be overridden.
+var snapshotDir = filepath.Join("testdata", "snapshots")
+
+// snapshots keeps track of the snapshots taken by each running test, and of all snapshot files used
+var snapshots = struct {
+	sync.Mutex
+	counts map[string]int
+	used   map[string]bool
+}{counts: map[string]int{}, used: map[string]bool{}}
+
+var unsafeFileChars = regexp.MustCompile(`[^\w.-]+`)
+
+// Snap compares a serialized form of the value with a snapshot stored in a file under testdata/snapshots named
+// after the test and the number of the call to Snap within the test, i.e. `TestName_1.snap`. Strings are stored
+// as is, and other values as indented JSON (or in the form produced by fmt's %+v if they cannot be marshaled to
+// JSON). A missing snapshot, or a snapshot that is different from the value, is a failure unless UpdateGolden()
+// returns true, in which case the snapshot is written (see CheckGolden). Platform specific variants of snapshot
+// files, such as `TestName_1_windows.snap`, are selected as for CheckGolden.
+//
+// When a test that called Snap finishes, snapshot files for the test that were not used by any call to Snap are
+// reported as orphans (and removed in update mode). See SnapshotMain for detection of orphans of removed tests.
+func Snap(value interface{}, t testing.TB) {
+	t.Helper()
+	name := unsafeFileChars.ReplaceAllString(t.Name(), "_")
+	snapshots.Lock()
+	n := snapshots.counts[name] + 1
+	snapshots.counts[name] = n
+	filename := filepath.Join(snapshotDir, fmt.Sprintf("%s_%d.snap", name, n))
+	snapshots.used[filename] = true
+	snapshots.Unlock()
+	if n == 1 {
+		t.Cleanup(func() { checkSnapshotOrphans(name, t) })
+	}
+	CheckGolden(filename, snapshotText(value), t)
+}
+
+func snapshotText(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	if data, err := json.MarshalIndent(value, "", "  "); err == nil {
+		return string(data) + "\n"
+	}
+	return fmt.Sprintf("%+v\n", value)
+}
+
+// checkSnapshotOrphans reports snapshots of the named test with a number higher than the number of calls to Snap
+// made by the test, and resets the count so that the test can run again.
+func checkSnapshotOrphans(name string, t testing.TB) {
+	t.Helper()
+	snapshots.Lock()
+	n := snapshots.counts[name]
+	delete(snapshots.counts, name)
+	snapshots.Unlock()
+	files, _ := filepath.Glob(filepath.Join(snapshotDir, name+"_*.snap"))
+	var orphans []string
+	for _, f := range files {
+		i, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(filepath.Base(snapshotBase(f)), name+"_"), ".snap"))
+		if err == nil && i > n {
+			orphans = append(orphans, f)
+		}
+	}
+	if len(orphans) > 0 {
+		if UpdateGolden() {
+			removeSnapshots(orphans, t.Logf)
+			return
+		}
+		t.Errorf("orphaned snapshots (run with -update to remove them): %s", strings.Join(orphans, ", "))
+	}
+}
+
+// SnapshotMain runs the tests with m.Run and then, if all tests were run (i.e. no -run or -skip flag was given)
+// and passed, reports snapshot files that were not used by any test (and removes them in update mode). It returns
+// the exit code. Use it from TestMain as:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testutils.SnapshotMain(m))
+//	}
+func SnapshotMain(m *testing.M) int {
+	code := m.Run()
+	if code != 0 || flagSet("test.run") || flagSet("test.skip") {
+		return code
+	}
+	orphans := unusedSnapshots()
+	if len(orphans) == 0 {
+		return code
+	}
+	if UpdateGolden() {
+		removeSnapshots(orphans, func(format string, args ...interface{}) { fmt.Printf(format+"\n", args...) })
+		return code
+	}
+	fmt.Printf("orphaned snapshots (run with -update to remove them): %s\n", strings.Join(orphans, ", "))
+	return 1
+}
+
+// unusedSnapshots returns the sorted names of the snapshot files that were not used by any call to Snap. A platform
+// specific variant of a snapshot (see CheckGolden) counts as used when the snapshot is used, also on other platforms.
+func unusedSnapshots() []string {
+	files, _ := filepath.Glob(filepath.Join(snapshotDir, "*.snap"))
+	var unused []string
+	snapshots.Lock()
+	for _, f := range files {
+		if !snapshots.used[snapshotBase(f)] {
+			unused = append(unused, f)
+		}
+	}
+	snapshots.Unlock()
+	sort.Strings(unused)
+	return unused
+}
+
+// snapshotBase returns the name of the snapshot file without the suffix of a platform specific variant for any
+// known platform, such as TestName_1.snap for TestName_1_linux_386.snap. The name without the suffix must end with
+// the number of the snapshot.
+func snapshotBase(filename string) string {
+	dir, base := filepath.Split(strings.TrimSuffix(filename, ".snap"))
+	for _, o := range knownGOOS {
+		for _, a := range knownGOARCH {
+			for _, suffix := range variantSuffixes(o, a) {
+				if b := strings.TrimSuffix(base, suffix); b != base && endsWithNumber(b) {
+					return dir + b + ".snap"
+				}
+			}
+		}
+	}
+	return filename
+}
+
+// endsWithNumber returns true if the part of the name after the last '_' is a number
+func endsWithNumber(name string) bool {
+	_, err := strconv.Atoi(name[strings.LastIndexByte(name, '_')+1:])
+	return err == nil
+}
+
+func flagSet(name string) bool {
+	f := flag.Lookup(name)
+	return f != nil && f.Value.String() != ""
+}
+
+func removeSnapshots(files []string, logf func(format string, args ...interface{})) {
+	for _, f := range files {
+		if err := os.Remove(f); err == nil {
+			logf("removed orphaned snapshot %s", f)
+		}
+	}
+}
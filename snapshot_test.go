@@ -0,0 +1,113 @@
+package testutils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// snapTB is a messageTB with a name that runs its cleanups when told to
+type snapTB struct {
+	messageTB
+	name     string
+	cleanups []func()
+}
+
+func (s *snapTB) Name() string { return s.name }
+
+func (s *snapTB) Cleanup(f func()) { s.cleanups = append(s.cleanups, f) }
+
+func (s *snapTB) Logf(format string, args ...interface{}) {}
+
+func (s *snapTB) finish() {
+	for i := len(s.cleanups) - 1; i >= 0; i-- {
+		s.cleanups[i]()
+	}
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(SnapshotMain(m))
+}
+
+func TestSnap(t *testing.T) {
+	Snap(map[string]interface{}{"name": "a", "values": []int{1, 2}}, t)
+	Snap("plain text\n", t)
+	Snap(3.5, t)
+	t.Run("sub test", func(t *testing.T) {
+		Snap(struct{ A int }{1}, t)
+	})
+	if UpdateGolden() {
+		return
+	}
+
+	st := &snapTB{name: "TestSnap/sub test"}
+	Snap(struct{ A int }{2}, st)
+	st.finish()
+	CheckEqual(1, len(st.messages), t)
+}
+
+func Test_snapshotText(t *testing.T) {
+	CheckEqual("x", snapshotText("x"), t)
+	CheckEqual("[\n  1,\n  2\n]\n", snapshotText([]int{1, 2}), t)
+	CheckEqual("{C:<nil>}\n", snapshotText(struct{ C chan int }{}), t)
+}
+
+func TestSnap_orphans(t *testing.T) {
+	if UpdateGolden() {
+		t.Skip("orphans are removed in update mode")
+	}
+	saved := snapshotDir
+	defer func() { snapshotDir = saved }()
+	snapshotDir = t.TempDir()
+	for _, n := range []string{"TestX_1.snap", "TestX_2.snap", "TestX_3.snap", "TestXY_3.snap"} {
+		if err := os.WriteFile(filepath.Join(snapshotDir, n), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st := &snapTB{name: "TestX"}
+	Snap("x", st)
+	Snap("x", st)
+	st.finish()
+	CheckEqual([]string{"orphaned snapshots (run with -update to remove them): " + filepath.Join(snapshotDir, "TestX_3.snap")}, st.messages, t)
+
+	// counts are reset when the test finishes
+	st = &snapTB{name: "TestX"}
+	Snap("x", st)
+	CheckEqual(0, len(st.messages), t)
+	// reset the counts so that the test can run again with -count
+	st.finish()
+}
+
+func TestSnap_variants(t *testing.T) {
+	if UpdateGolden() {
+		t.Skip("orphans are removed in update mode")
+	}
+	savedDir, savedUsed := snapshotDir, snapshots.used
+	defer func() { snapshotDir, snapshots.used = savedDir, savedUsed }()
+	snapshotDir = t.TempDir()
+	snapshots.used = map[string]bool{}
+	for _, n := range []string{"TestS_1_" + goos + ".snap", "TestS_1_plan9_mips.snap", "TestS_1_linux_386.snap", "TestS_2_" + goos + ".snap", "TestT_1.snap"} {
+		if err := os.WriteFile(filepath.Join(snapshotDir, n), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	st := &snapTB{name: "TestS"}
+	Snap("x", st)
+	st.finish()
+	CheckEqual([]string{"orphaned snapshots (run with -update to remove them): " + filepath.Join(snapshotDir, "TestS_2_"+goos+".snap")}, st.messages, t)
+	CheckEqual([]string{
+		filepath.Join(snapshotDir, "TestS_2_"+goos+".snap"),
+		filepath.Join(snapshotDir, "TestT_1.snap"),
+	}, unusedSnapshots(), t)
+}
+
+func Test_snapshotBase(t *testing.T) {
+	CheckEqual(filepath.Join("d_1", "TestA_1.snap"), snapshotBase(filepath.Join("d_1", "TestA_1.snap")), t)
+	CheckEqual("TestA_2.snap", snapshotBase("TestA_2_linux.snap"), t)
+	CheckEqual("TestA_case_3_2.snap", snapshotBase("TestA_case_3_2_linux_amd64.snap"), t)
+	CheckEqual("TestA_x.snap", snapshotBase("TestA_x.snap"), t)
+	CheckEqual("TestX_1.snap", snapshotBase("TestX_1_linux_386.snap"), t)
+	CheckEqual("TestX_1.snap", snapshotBase("TestX_1_386.snap"), t)
+	CheckEqual("TestX_386.snap", snapshotBase("TestX_386.snap"), t)
+	CheckEqual("TestX_1_other.snap", snapshotBase("TestX_1_other.snap"), t)
+}
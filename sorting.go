@@ -0,0 +1,89 @@
+package testutils
+
+import (
+	"reflect"
+	"testing"
+)
+
+// CheckStableSort checks that the sort function sorts a copy of the input according to less, and that it is stable,
+// i.e. that elements with equal keys (neither is less than the other) keep their original relative order. Elements
+// of the output are matched to the input using reflect.DeepEqual, so elements with equal keys should differ in some
+// other way for the check to be meaningful. On failure the first pair of elements that is out of order, or that
+// has had its relative order reversed, is reported.
+func CheckStableSort[T any](input []T, sortFn func([]T), less func(a, b T) bool, t testing.TB) {
+	out := make([]T, len(input))
+	copy(out, input)
+	sortFn(out)
+	if len(out) != len(input) {
+		t.Helper()
+		t.Fatalf("Expected sort to keep %d elements, got %d", len(input), len(out))
+		return
+	}
+
+	// origin[i] is the index in input of the element at index i in out
+	origin := make([]int, len(out))
+	used := make([]bool, len(input))
+	for i, o := range out {
+		origin[i] = -1
+		for j, in := range input {
+			if !used[j] && reflect.DeepEqual(in, o) {
+				origin[i], used[j] = j, true
+				break
+			}
+		}
+		if origin[i] < 0 {
+			t.Helper()
+			t.Fatalf("Expected sort to permute the input, but element %v at index %d is not in the input", o, i)
+			return
+		}
+	}
+	for i := 1; i < len(out); i++ {
+		a, b := out[i-1], out[i]
+		switch {
+		case less(b, a):
+			t.Helper()
+			t.Fatalf("Expected sorted output, but %v at index %d is less than %v at index %d", b, i, a, i-1)
+			return
+		case !less(a, b) && origin[i-1] > origin[i]:
+			t.Helper()
+			t.Fatalf("Expected stable sort, but %v (input index %d) and %v (input index %d) have equal keys and are reversed at index %d and %d",
+				b, origin[i], a, origin[i-1], i-1, i)
+			return
+		}
+	}
+}
+
+// CheckHeapInvariant checks that the slice has the heap property for the given less function, i.e. that no element
+// is less than its parent, where the parent of the element at index i is at index (i-1)/2 (the layout used by
+// container/heap). With a less function of a < b this is a min-heap. On failure the first violating element and
+// its parent are reported.
+func CheckHeapInvariant[T any](heap []T, less func(a, b T) bool, t testing.TB) {
+	for i := 1; i < len(heap); i++ {
+		parent := (i - 1) / 2
+		if less(heap[i], heap[parent]) {
+			t.Helper()
+			t.Fatalf("Expected heap invariant, but %v at index %d is less than its parent %v at index %d", heap[i], i, heap[parent], parent)
+			return
+		}
+	}
+}
+
+// CheckBSTInvariant checks the ordering of a binary search tree by walking it in order. The walk function must call
+// yield with each node value of the tree in order and stop when yield returns false. The values must then be
+// strictly increasing according to less. On failure the first node that is not greater than the node before it is
+// reported together with its position in the walk.
+func CheckBSTInvariant[T any](walk func(yield func(T) bool), less func(a, b T) bool, t testing.TB) {
+	t.Helper()
+	var prev T
+	n := 0
+	walk(func(v T) bool {
+		if n > 0 && !less(prev, v) {
+			t.Helper()
+			t.Fatalf("Expected binary search tree ordering, but node %v at in-order position %d is not greater than the node %v before it", v, n, prev)
+			return false
+		}
+		prev = v
+		n++
+		return true
+	})
+}
@@ -0,0 +1,67 @@
+package testutils
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestCheckStableSort(t *testing.T) {
+	type item struct {
+		Key  int
+		Name string
+	}
+	input := []item{{2, "a"}, {1, "b"}, {2, "c"}, {1, "d"}, {2, "a"}}
+	less := func(a, b item) bool { return a.Key < b.Key }
+	CheckStableSort(input, func(s []item) { sort.SliceStable(s, func(i, j int) bool { return less(s[i], s[j]) }) }, less, t)
+	CheckStableSort(nil, func(s []item) {}, less, t)
+
+	m := &messageTB{}
+	// reversing the input, then sorting stably reverses the order of equal keys
+	CheckStableSort(input, func(s []item) {
+		for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+			s[i], s[j] = s[j], s[i]
+		}
+		sort.SliceStable(s, func(i, j int) bool { return less(s[i], s[j]) })
+	}, less, m)
+	CheckStableSort(input, func(s []item) {}, less, m)
+	CheckStableSort(input, func(s []item) { s[0] = item{3, "x"} }, less, m)
+	CheckEqual([]string{
+		"Expected stable sort, but {1 b} (input index 1) and {1 d} (input index 3) have equal keys and are reversed at index 0 and 1",
+		"Expected sorted output, but {1 b} at index 1 is less than {2 a} at index 0",
+		"Expected sort to permute the input, but element {3 x} at index 0 is not in the input",
+	}, m.messages, t)
+}
+
+func TestCheckHeapInvariant(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	CheckHeapInvariant([]int{1, 3, 2, 7, 4, 5}, less, t)
+	CheckHeapInvariant(nil, less, t)
+
+	m := &messageTB{}
+	CheckHeapInvariant([]int{1, 3, 2, 7, 2}, less, m)
+	CheckEqual([]string{"Expected heap invariant, but 2 at index 4 is less than its parent 3 at index 1"}, m.messages, t)
+}
+
+type testTree struct {
+	Left, Right *testTree
+	Value       int
+}
+
+func (n *testTree) walk(yield func(int) bool) bool {
+	return n == nil || n.Left.walk(yield) && yield(n.Value) && n.Right.walk(yield)
+}
+
+func TestCheckBSTInvariant(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	tree := &testTree{Value: 5, Left: &testTree{Value: 2, Right: &testTree{Value: 4}}, Right: &testTree{Value: 8}}
+	CheckBSTInvariant(func(yield func(int) bool) { tree.walk(yield) }, less, t)
+
+	m := &messageTB{}
+	tree.Left.Right.Value = 6
+	visited := 0
+	CheckBSTInvariant(func(yield func(int) bool) {
+		tree.walk(func(v int) bool { visited++; return yield(v) })
+	}, less, m)
+	CheckEqual([]string{"Expected binary search tree ordering, but node 5 at in-order position 2 is not greater than the node 6 before it"}, m.messages, t)
+	CheckEqual(3, visited, t)
+}
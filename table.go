@@ -0,0 +1,35 @@
+package testutils
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// RunTable runs fn for each of the given cases as a subtest of t. The subtest is named after the case's
+// Name field if the case is a struct (or a pointer to a struct) with a non empty string field called Name,
+// otherwise it is named after the index of the case. The Tester given to fn has the index of the case set.
+func RunTable[C any](cases []C, fn func(tt Tester, c C), t testing.TB) {
+	t.Helper()
+	tt := NewTester(t)
+	for i, c := range cases {
+		i, c := i, c
+		tt.Run(caseName(c, i), func(ct Tester) {
+			fn(ct.At(i), c)
+		})
+	}
+}
+
+// caseName returns the value of the Name field of c or the given index if there is no such field
+func caseName(c interface{}, index int) string {
+	v := reflect.ValueOf(c)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if f := v.FieldByName("Name"); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+			return f.String()
+		}
+	}
+	return strconv.Itoa(index)
+}
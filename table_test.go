@@ -0,0 +1,33 @@
+package testutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunTable(t *testing.T) {
+	type testCase struct {
+		Name     string
+		In       string
+		Expected string
+	}
+	var prefixes []string
+	RunTable([]testCase{
+		{Name: "lower", In: "a", Expected: "A"},
+		{In: "B", Expected: "B"},
+	}, func(tt Tester, c testCase) {
+		prefixes = append(prefixes, tt.(*tester).prefix())
+		tt.CheckEqual(c.Expected, strings.ToUpper(c.In))
+	}, t)
+	CheckEqual([]string{"lower: [0] ", "1: [1] "}, prefixes, t)
+}
+
+func Test_caseName(t *testing.T) {
+	type named struct{ Name string }
+	type unnamed struct{ Name int }
+	CheckEqual("x", caseName(named{Name: "x"}, 1), t)
+	CheckEqual("x", caseName(&named{Name: "x"}, 1), t)
+	CheckEqual("1", caseName(named{}, 1), t)
+	CheckEqual("2", caseName(unnamed{Name: 3}, 2), t)
+	CheckEqual("3", caseName("x", 3), t)
+}
@@ -0,0 +1,54 @@
+package testutils
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// FakeTerminal is an io.Writer that captures output written to it while pretending to be a terminal of a given
+// size. Code that decides how to wrap or style its output can check for the IsTerminal and Size methods on the
+// writer it is given, or read the COLUMNS and LINES environment variables set by WithFakeTerminal.
+type FakeTerminal struct {
+	bytes.Buffer
+	width, height int
+}
+
+// WithFakeTerminal returns a new FakeTerminal with the given size. For the duration of the test the COLUMNS and
+// LINES environment variables are set to the size. Since environment variables are changed, this cannot be used in
+// parallel tests. Use a tester created with the AssumeTerminal option to also get diffs as when the output of the
+// test is a terminal.
+func WithFakeTerminal(width, height int, t testing.TB) *FakeTerminal {
+	t.Setenv("COLUMNS", strconv.Itoa(width))
+	t.Setenv("LINES", strconv.Itoa(height))
+	return &FakeTerminal{width: width, height: height}
+}
+
+// IsTerminal returns true
+func (ft *FakeTerminal) IsTerminal() bool {
+	return true
+}
+
+// Size returns the width and height of the terminal
+func (ft *FakeTerminal) Size() (width, height int) {
+	return ft.width, ft.height
+}
+
+// Lines returns the lines of the captured output with ANSI escape sequences removed
+func (ft *FakeTerminal) Lines() []string {
+	return strings.Split(strings.TrimSuffix(StripANSI(ft.String()), "\n"), "\n")
+}
+
+// CheckFitsWidth checks that no line of the captured output (with ANSI escape sequences removed) is wider than
+// the terminal. On failure the first line that is too wide is reported.
+func (ft *FakeTerminal) CheckFitsWidth(t testing.TB) {
+	for i, line := range ft.Lines() {
+		if n := utf8.RuneCountInString(line); n > ft.width {
+			t.Helper()
+			t.Fatalf("Expected output to fit terminal width %d, line %d has width %d: %q", ft.width, i+1, n, line)
+			return
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package testutils
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestWithFakeTerminal(t *testing.T) {
+	t.Run("fixture", func(t *testing.T) {
+		term := WithFakeTerminal(10, 5, t)
+		CheckEqual("10", os.Getenv("COLUMNS"), t)
+		CheckEqual("5", os.Getenv("LINES"), t)
+		w, h := term.Size()
+		CheckEqual(10, w, t)
+		CheckEqual(5, h, t)
+
+		fmt.Fprintln(term, "\x1b[1m0123456789\x1b[0m")
+		fmt.Fprintln(term, "ok")
+		CheckEqual([]string{"0123456789", "ok"}, term.Lines(), t)
+		term.CheckFitsWidth(t)
+
+		fmt.Fprintln(term, "01234567890")
+		ensureFailed(t, func(ft *testing.T) {
+			term.CheckFitsWidth(ft)
+		})
+	})
+	CheckFalse(os.Getenv("COLUMNS") == "10", t)
+}
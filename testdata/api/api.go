@@ -0,0 +1,50 @@
+// Package api is used to test CheckPackageAPIStable
+package api
+
+import "io"
+
+// Version is exported
+const Version = "1.0"
+
+const internal = 1
+
+// Colors
+const (
+	Red, Green int = 1, 2
+	blue           = 3
+)
+
+var Default *Thing
+
+// Thing is a struct with exported and unexported fields
+type Thing struct {
+	Name    string `json:"name"`
+	a, B    int
+	private bool
+	io.Reader
+}
+
+// Doer is an interface
+type Doer interface {
+	Do(x int) error
+	undo()
+}
+
+type List[T any] []T
+
+type hidden struct{}
+
+// New returns a new Thing
+func New(name string) *Thing {
+	return &Thing{Name: name}
+}
+
+func (t *Thing) Rename(name string) { t.Name = name }
+
+func (t Thing) private() {}
+
+func (hidden) Exported() {}
+
+func (l List[T]) Len() int { return len(l) }
+
+func helper() {}
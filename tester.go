@@ -11,19 +11,59 @@ import (
 	"github.com/sergi/go-diff/diffmatchpatch"
 )
 
-// Tester wraps a *testing.T and an Index for iterative tests
+// Tester wraps a testing.TB and an Index for iterative tests
 type tester struct {
-	t        *testing.T
-	index    int
-	indexSet bool
+	t        testing.TB
+	name     string
+	indices  []int
+	label    string
+	msg      string
+	nonFatal bool
+	soft     *softFailures
+
+	// options
+	noColor        bool
+	terminal       bool
+	unified        bool
+	diffLimit      int
+	msgPrefix      string
+	normalizers    []Normalizer
+	nilEqualsEmpty bool
+	ignoreTimeZone bool
+}
+
+// softFailures holds the failures recorded by a soft tester
+type softFailures struct {
+	messages []string
 }
 
 // Tester describes a testing context which can be modified to output an index for iterative testing
 type Tester interface {
-	// At sets index and returns this for convenient chaining as tt.At(i).CheckXXX()
-	At(index int) Tester
+	// At sets index and returns this for convenient chaining as tt.At(i).CheckXXX(). Multiple indexes can be
+	// given for nested loops, i.e. tt.At(row, col) which produces a failure prefix like "[2][5]".
+	At(indices ...int) Tester
+	// Next increments the (last) index, or sets it to 0 if there is no index, clears the label, and returns this.
+	// Calling tt.Next() once at the start of each iteration of a loop makes the index follow the iteration.
+	Next() Tester
+	// AtName sets a case label (and clears the index) and returns this for convenient chaining as
+	// tt.AtName("empty input").CheckXXX()
+	AtName(label string) Tester
+	// AtCase sets both index and case label and returns this for convenient chaining as tt.AtCase(i, name).CheckXXX()
+	AtCase(index int, label string) Tester
+	// Expect returns a Tester with the same index that reports failures with Errorf instead of Fatalf, for
+	// convenient chaining as tt.Expect().CheckXXX()
+	Expect() Tester
+	// Msgf returns a Tester with the same index that appends the formatted context message to failure messages,
+	// for convenient chaining as tt.Msgf("while parsing %q", input).CheckXXX()
+	Msgf(format string, args ...interface{}) Tester
+	// Run runs f as a subtest of the wrapped test or benchmark with the given name. The Tester given to f wraps
+	// the subtest, has its own index, and prefixes failure messages with the name.
+	Run(name string, f func(tt Tester)) bool
 	CheckEqual(expected interface{}, got interface{})
 	CheckNotEqual(expected interface{}, got interface{})
+	// CheckAll compares the expected and got values of all the pairs as by CheckEqual, and reports all mismatches
+	// in a single failure
+	CheckAll(pairs ...Pair)
 	CheckNumericGreater(expected interface{}, got interface{})
 	CheckNumericLess(expected interface{}, got interface{})
 	CheckEqualAndNoError(expected interface{}, got interface{}, gotError error)
@@ -37,24 +77,105 @@ type Tester interface {
 	CheckAfterOrEqual(expected, got time.Time, add ...time.Duration)
 	CheckBefore(expected, got time.Time, add ...time.Duration)
 	CheckBeforeOrEqual(expected, got time.Time, add ...time.Duration)
+	CheckTimeWithin(expected, got time.Time, delta time.Duration)
+	CheckTimesEqual(expected, got time.Time)
 	CheckMatches(expected interface{}, got string)
 	Fatalf(fmt string, args ...interface{})
 	CheckTruef(predicate bool, fmt string, args ...interface{})
 	CheckStringSlicesEqual(expected, got []string)
 	CheckTextEqual(expected, got string)
+	CheckTextEqualStripANSI(expected, got string)
+	CheckTextLinesEqual(expected, got string)
+}
+
+// SoftTester is a Tester that records every failed check instead of stopping the test at the first failure.
+// All recorded failures are reported together in a single call to Fatalf by Flush.
+type SoftTester interface {
+	Tester
+	// Flush reports all failures recorded since the last Flush (if any) in a single call to Fatalf
+	Flush()
 }
 
-// NewTester returns a new tester that supports setting the Index
-func NewTester(t *testing.T) Tester {
+// NewSoftTester returns a new SoftTester. Flush is registered to be called when the test and all its
+// subtests complete, so failures recorded after the last explicit call to Flush are never lost.
+func NewSoftTester(t testing.TB) SoftTester {
+	tt := &tester{t: t, soft: &softFailures{}}
+	t.Cleanup(tt.Flush)
+	return tt
+}
+
+// NewTester returns a new tester that supports setting the Index. The given testing.TB can be a *testing.T,
+// a *testing.B, or a *testing.F.
+func NewTester(t testing.TB) Tester {
 	return &tester{t: t}
 }
 
-func (tt *tester) At(index int) Tester {
-	tt.indexSet = true
-	tt.index = index
+func (tt *tester) At(indices ...int) Tester {
+	tt.indices = append([]int(nil), indices...)
+	tt.label = ""
+	return tt
+}
+
+func (tt *tester) Next() Tester {
+	if len(tt.indices) == 0 {
+		return tt.At(0)
+	}
+	indices := append([]int(nil), tt.indices...)
+	indices[len(indices)-1]++
+	return tt.At(indices...)
+}
+
+func (tt *tester) AtName(label string) Tester {
+	tt.indices = nil
+	tt.label = label
+	return tt
+}
+
+func (tt *tester) AtCase(index int, label string) Tester {
+	tt.indices = []int{index}
+	tt.label = label
 	return tt
 }
 
+func (tt *tester) Expect() Tester {
+	et := *tt
+	et.nonFatal = true
+	return &et
+}
+
+func (tt *tester) Msgf(format string, args ...interface{}) Tester {
+	mt := *tt
+	mt.msg = fmt.Sprintf(format, args...)
+	return &mt
+}
+
+func (tt *tester) Run(name string, f func(tt Tester)) bool {
+	tt.t.Helper()
+	child := func(t testing.TB) Tester {
+		// the child inherits mode and options, but not index, label, and context message
+		ct := &tester{t: t, name: name, nonFatal: tt.nonFatal, noColor: tt.noColor, terminal: tt.terminal, unified: tt.unified,
+			diffLimit: tt.diffLimit, msgPrefix: tt.msgPrefix, normalizers: tt.normalizers,
+			nilEqualsEmpty: tt.nilEqualsEmpty, ignoreTimeZone: tt.ignoreTimeZone}
+		if tt.name != "" {
+			ct.name = tt.name + "/" + name
+		}
+		if tt.soft != nil {
+			ct.soft = &softFailures{}
+			t.Cleanup(ct.Flush)
+		}
+		return ct
+	}
+	switch t := tt.t.(type) {
+	case *testing.T:
+		return t.Run(name, func(st *testing.T) { f(child(st)) })
+	case *testing.B:
+		return t.Run(name, func(sb *testing.B) { f(child(sb)) })
+	default:
+		tt.Fatalf("Run: subtests are not supported by %T", tt.t)
+		return false
+	}
+}
+
 func (tt *tester) unequalValues(e, g interface{}) {
 	tt.t.Helper()
 	tt.Fatalf("Expected Equal: %T %v, got %T %v", e, e, g, g)
@@ -64,28 +185,122 @@ func (tt *tester) equalValues(e, g interface{}) {
 	tt.Fatalf("Expected Noti Equal: %T %v, got %T %v", e, e, g, g)
 }
 
+// Fatalf reports a failure prefixed with the name, index and label (if set), and followed by the context message
+// (if set). The test stops unless the tester was obtained
+// from Expect() in which case the failure is reported with Errorf, or if this is a soft tester in which case
+// the failure is recorded and reported on Flush.
 func (tt *tester) Fatalf(str string, args ...interface{}) {
 	tt.t.Helper()
-	if !tt.indexSet {
-		tt.t.Fatalf(str, args...)
+	msg := tt.msgPrefix + tt.prefix() + fmt.Sprintf(str, args...)
+	if tt.msg != "" {
+		msg += " (" + tt.msg + ")"
+	}
+	switch {
+	case tt.soft != nil:
+		tt.soft.messages = append(tt.soft.messages, msg)
+	case tt.nonFatal:
+		tt.t.Error(msg)
+	default:
+		tt.t.Fatal(msg)
+	}
+}
+
+// prefix returns the name, index and label prefix of failure messages
+func (tt *tester) prefix() string {
+	p := ""
+	if tt.name != "" {
+		p = tt.name + ": "
 	}
-	indexPart := fmt.Sprintf("[%d] ", tt.index)
-	tt.t.Fatalf(indexPart+str, args...)
+	last := len(tt.indices) - 1
+	for i, index := range tt.indices {
+		if i == last && tt.label != "" {
+			p += fmt.Sprintf("[%d: %s]", index, tt.label)
+		} else {
+			p += fmt.Sprintf("[%d]", index)
+		}
+	}
+	if last < 0 && tt.label != "" {
+		p += fmt.Sprintf("[%s]", tt.label)
+	}
+	if last >= 0 || tt.label != "" {
+		p += " "
+	}
+	return p
+}
+
+func (tt *tester) Flush() {
+	if tt.soft == nil || len(tt.soft.messages) == 0 {
+		return
+	}
+	msgs := tt.soft.messages
+	tt.soft.messages = nil
+	tt.t.Helper()
+	tt.t.Fatalf("%d checks failed:\n%s", len(msgs), strings.Join(msgs, "\n"))
 }
 
 // CheckEqual checks if two values are deeply equal and calls t.Fatalf if not
 func (tt *tester) CheckEqual(expected interface{}, got interface{}) {
-	nc := numericCompare(expected, got)
-	if !(nc == 0 || nc == -2 && reflect.DeepEqual(expected, got)) {
+	if !tt.valuesEqual(expected, got) {
 		tt.t.Helper()
 		tt.unequalValues(expected, got)
 	}
 }
 
+// valuesEqual returns true if the values are numerically equal, or deeply equal (with nil and empty slices and maps
+// being equal if the tester has the NilEqualsEmpty option, and time.Time values being compared as instants if it has
+// the IgnoreTimeZone option)
+func (tt *tester) valuesEqual(expected, got interface{}) bool {
+	nc := numericCompare(expected, got)
+	if nc != -2 {
+		return nc == 0
+	}
+	if tt.nilEqualsEmpty || tt.ignoreTimeZone {
+		var notes []string
+		opts := diffOptions{nilEqualsEmpty: tt.nilEqualsEmpty, sameLocation: !tt.ignoreTimeZone, locationNotes: &notes}
+		_, differs := diffValues(reflect.ValueOf(expected), reflect.ValueOf(got), "value", opts)
+		if !differs && testing.Verbose() {
+			for _, note := range notes {
+				tt.t.Logf("%s%s", tt.prefix(), note)
+			}
+		}
+		return !differs
+	}
+	return reflect.DeepEqual(expected, got)
+}
+
+// Pair is a labeled pair of an expected and a got value, used with CheckAll
+type Pair struct {
+	Label    string
+	Expected interface{}
+	Got      interface{}
+}
+
+// CheckAll compares the expected and got values of each pair as by CheckEqual, and calls t.Fatalf with all
+// mismatches if any. This is useful when checking several results of one call, i.e.
+//
+//	a, b, err := f()
+//	tt.CheckAll(Pair{"a", 1, a}, Pair{"b", "x", b}, Pair{"err", nil, err})
+func (tt *tester) CheckAll(pairs ...Pair) {
+	var mismatches []string
+	for i, p := range pairs {
+		if tt.valuesEqual(p.Expected, p.Got) {
+			continue
+		}
+		label := p.Label
+		if label == "" {
+			label = fmt.Sprintf("#%d", i)
+		}
+		mismatches = append(mismatches, fmt.Sprintf("  %s: expected %T %v, got %T %v", label, p.Expected, p.Expected, p.Got, p.Got))
+	}
+	if len(mismatches) > 0 {
+		tt.t.Helper()
+		tt.Fatalf("Expected all equal, %d of %d differ:\n%s", len(mismatches), len(pairs), strings.Join(mismatches, "\n"))
+	}
+}
+
 // CheckNotEqual checks if two values are deeply equal and calls t.Fatalf if not
 func (tt *tester) CheckNotEqual(expected interface{}, got interface{}) {
-	nc := numericCompare(expected, got)
-	if nc == 0 || nc == -2 && reflect.DeepEqual(expected, got) {
+	if tt.valuesEqual(expected, got) {
 		tt.t.Helper()
 		tt.equalValues(expected, got)
 	}
@@ -111,7 +326,7 @@ func (tt *tester) CheckNumericLess(expected interface{}, got interface{}) {
 func (tt *tester) CheckEqualAndNoError(expected interface{}, got interface{}, gotError error) {
 	tt.t.Helper()
 	tt.CheckNotError(gotError)
-	if !reflect.DeepEqual(expected, got) {
+	if !tt.valuesEqual(expected, got) {
 		tt.unequalValues(expected, got)
 	}
 }
@@ -216,6 +431,33 @@ func (tt *tester) CheckBeforeOrEqual(expected, got time.Time, add ...time.Durati
 	}
 }
 
+// CheckTimeWithin checks if the absolute difference between the actual and the expected time is at most delta
+func (tt *tester) CheckTimeWithin(expected, got time.Time, delta time.Duration) {
+	diff := got.Sub(expected)
+	if diff > delta || diff < -delta {
+		tt.t.Helper()
+		tt.Fatalf("Expected: time within %v of %v, got %v (diff %v)", delta, expected, got, diff)
+	}
+}
+
+// CheckTimesEqual checks if the actual and the expected time are the same instant in the same location. The
+// monotonic clock reading is ignored. The times are compared in UTC if the tester has the IgnoreTimeZone option.
+func (tt *tester) CheckTimesEqual(expected, got time.Time) {
+	expected, got = expected.Round(0), got.Round(0)
+	if tt.ignoreTimeZone {
+		expected, got = expected.UTC(), got.UTC()
+	}
+	if !expected.Equal(got) {
+		tt.t.Helper()
+		tt.Fatalf("Expected: time %v, got %v (diff %v)", expected, got, got.Sub(expected))
+		return
+	}
+	if el, gl := expected.Location().String(), got.Location().String(); el != gl {
+		tt.t.Helper()
+		tt.Fatalf("Expected: time %v in location %s, got %v in location %s", expected, el, got, gl)
+	}
+}
+
 // CheckMatches checks expected regular expression is matched by the given string and calls t.Fatalf if not
 //
 // The expected regular expression can be either a *regexp.Regexp or a string that represents a valid regexp
@@ -230,10 +472,12 @@ func (tt *tester) CheckMatches(expected interface{}, got string) {
 		if err != nil {
 			tt.t.Helper()
 			tt.Fatalf("CheckMatches: illegal regexp %q", expected)
+			return
 		}
 	default:
 		tt.t.Helper()
 		tt.Fatalf("CheckMatches: first argument must be a regexp or a string, got %T %v", expected, expected)
+		return
 	}
 	if !rx.MatchString(got) {
 		tt.t.Helper()
@@ -247,82 +491,256 @@ func (tt *tester) CheckTruef(predicate bool, fmt string, args ...interface{}) {
 		return
 	}
 	tt.t.Helper()
-	tt.t.Fatalf(fmt, args...)
+	tt.Fatalf(fmt, args...)
 }
 
 // CheckStringSlicesEqual
 func (tt *tester) CheckStringSlicesEqual(expected, got []string) {
-	diff, ok := produceDiff(expected, got)
+	if len(tt.normalizers) > 0 {
+		expected = tt.normalizeLines(expected)
+		got = tt.normalizeLines(got)
+	}
+	var diff string
+	var ok bool
+	if tt.unified {
+		diff = unifiedDiff(expected, got)
+		ok = diff == ""
+	} else {
+		diff, ok = produceDiff(expected, got, tt.maxDiffLines())
+	}
 	if !ok {
 		tt.t.Helper()
-		tt.t.Fatalf("slices not equal - see diff:\n%s", diff)
+		tt.Fatalf("slices not equal - see diff:\n%s", diff)
 	}
 }
 
-// Produces expected and actual interleaved with a not if the are equal or not. Returns ok if there is no diff
-// and a each index below each other output for easy human comparison of mismatched result.
-func produceDiff(expected, got []string) (diff string, ok bool) {
-	cmpE := expected
-	cmpG := got
-	lE := len(expected)
-	lG := len(got)
-	if lE < lG {
-		cmpE = make([]string, lG)
-		copy(cmpE, expected)
-	}
-	if lE > lG {
-		cmpG = make([]string, lE)
-		copy(cmpG, got)
-	}
-	isDiff := false
+// Produces a line by line diff of expected and got aligned on their longest common subsequence, so that an
+// inserted or removed line is shown as a single added (+) or removed (-) line instead of making all following
+// lines unequal. Equal lines are marked with =. Each line shows its index in expected (e) and/or got (g).
+// Returns ok if there is no diff. The output stops after limit consecutive unequal lines unless limit is 0.
+func produceDiff(expected, got []string, limit int) (diff string, ok bool) {
 	var result []string
 	ok = true
 	badCount := 0
-	for i, e := range cmpE {
-		isDiff = (e != cmpG[i])
-		markerE := " = "
-		markerG := " = "
-		switch {
-		case isDiff && lE < lG && i >= lE:
-			markerE = "-! "
-			markerG = " !+"
-		case isDiff && lE > lG && i >= lG:
-			markerE = "+! "
-			markerG = " !-"
-		case isDiff:
-			markerE = " ! "
-			markerG = " ! "
+	for _, op := range alignLines(expected, got) {
+		switch op.kind {
+		case lineEqual:
+			result = append(result, fmt.Sprintf(" =  e[%d] g[%d] `%s`", op.e, op.g, expected[op.e]))
+			badCount = 0
+			continue
+		case lineRemoved:
+			result = append(result, fmt.Sprintf(" -  e[%d] `%s`", op.e, expected[op.e]))
+		case lineAdded:
+			result = append(result, fmt.Sprintf(" +  g[%d] `%s`", op.g, got[op.g]))
 		}
-		if isDiff {
-			ok = false
+		ok = false
+		badCount++
+		if limit > 0 && badCount > limit {
+			result = append(result, fmt.Sprintf("... stopping after %d unequal lines", limit))
+			break
 		}
+	}
+	return strings.Join(result, "\n"), ok
+}
 
-		// add expected and then got
-		if !isDiff {
-			result = append(result, fmt.Sprintf("%s eg[%d] `%s`", markerE, i, e))
-			badCount = 0
-		} else {
-			result = append(result, fmt.Sprintf("%s  e[%d] `%s`", markerE, i, e))
-			result = append(result, fmt.Sprintf("%s  g[%d] `%s`", markerG, i, cmpG[i]))
-			badCount++
-			if badCount > 2 {
-				result = append(result, "... stopping after 2 unequal lines")
-				break
+const (
+	lineEqual = iota
+	lineRemoved
+	lineAdded
+)
+
+// lineOp is one step in an alignment of two slices of lines. The e and g fields are the indexes in expected
+// and got respectively (only valid for the kinds of ops that refer to them).
+type lineOp struct {
+	kind int
+	e, g int
+}
+
+// alignLines returns the ops that transforms expected into got, aligned on their longest common subsequence.
+func alignLines(expected, got []string) []lineOp {
+	// trim common prefix and suffix to keep the LCS table small
+	pre := 0
+	for pre < len(expected) && pre < len(got) && expected[pre] == got[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(expected)-pre && suf < len(got)-pre && expected[len(expected)-1-suf] == got[len(got)-1-suf] {
+		suf++
+	}
+	e := expected[pre : len(expected)-suf]
+	g := got[pre : len(got)-suf]
+
+	// lcs[i][j] is the length of the longest common subsequence of e[i:] and g[j:]
+	lcs := make([][]int, len(e)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(g)+1)
+	}
+	for i := len(e) - 1; i >= 0; i-- {
+		for j := len(g) - 1; j >= 0; j-- {
+			if e[i] == g[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
 			}
 		}
 	}
-	return strings.Join(result, "\n"), ok
+
+	ops := make([]lineOp, 0, len(expected)+len(got))
+	for i := 0; i < pre; i++ {
+		ops = append(ops, lineOp{kind: lineEqual, e: i, g: i})
+	}
+	i, j := 0, 0
+	for i < len(e) || j < len(g) {
+		switch {
+		case i < len(e) && j < len(g) && e[i] == g[j]:
+			ops = append(ops, lineOp{kind: lineEqual, e: pre + i, g: pre + j})
+			i++
+			j++
+		case j == len(g) || i < len(e) && lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{kind: lineRemoved, e: pre + i})
+			i++
+		default:
+			ops = append(ops, lineOp{kind: lineAdded, g: pre + j})
+			j++
+		}
+	}
+	for k := 0; k < suf; k++ {
+		ops = append(ops, lineOp{kind: lineEqual, e: len(expected) - suf + k, g: len(got) - suf + k})
+	}
+	return ops
 }
 
 // CheckTextEqual behaves like CheckEqual in general, but in addition to just failing
 // a color coded diff will be produced in the error message making it easier to see where the
-// difference is (when run in a terminal window).
+// difference is (when run in a terminal window). When using the NoColor option, when the NO_COLOR or
+// TESTUTILS_NOCOLOR environment variables are set, or when output is not written to a terminal, deleted
+// text is instead marked as [-text-] and inserted text as {+text+}. With the UnifiedDiff option a line
+// based unified diff is produced.
 func (tt *tester) CheckTextEqual(expected, got string) {
+	expected = NormalizeText(expected, tt.normalizers...)
+	got = NormalizeText(got, tt.normalizers...)
 	if expected != got {
 		dmp := diffmatchpatch.New()
 		diffs := dmp.DiffMain(expected, got, false)
-		pretty := dmp.DiffPrettyText(diffs)
+		var pretty string
+		switch {
+		case tt.unified:
+			pretty = unifiedDiff(strings.Split(expected, "\n"), strings.Split(got, "\n"))
+		case tt.noColor || !colorEnabled(tt.terminal):
+			pretty = plainDiffText(diffs)
+		default:
+			pretty = dmp.DiffPrettyText(diffs)
+		}
 		tt.t.Helper()
-		tt.t.Fatalf("strings not equal - see diff:\n%s", pretty)
+		tt.Fatalf("strings not equal - see diff:\n%s", pretty)
+	}
+}
+
+// CheckTextEqualStripANSI behaves like CheckTextEqual but removes all ANSI escape sequences from the got
+// string before comparing. This makes it possible to check the content of colored CLI output separately from
+// its styling.
+func (tt *tester) CheckTextEqualStripANSI(expected, got string) {
+	tt.t.Helper()
+	tt.CheckTextEqual(expected, StripANSI(got))
+}
+
+// CheckTextLinesEqual behaves like CheckTextEqual but the two strings are split into lines and diffed line by
+// line. Each line of the diff shows the line numbers in expected and got, and long runs of equal lines are
+// left out. This is more readable than the character based diff of CheckTextEqual for long multi line output
+// such as rendered templates. The UnifiedDiff and DiffLimit options are honored.
+func (tt *tester) CheckTextLinesEqual(expected, got string) {
+	expected = NormalizeText(expected, tt.normalizers...)
+	got = NormalizeText(got, tt.normalizers...)
+	if expected == got {
+		return
+	}
+	el := strings.Split(expected, "\n")
+	gl := strings.Split(got, "\n")
+	var diff string
+	if tt.unified {
+		diff = unifiedDiff(el, gl)
+	} else {
+		diff = produceLineDiff(el, gl, tt.maxDiffLines())
+	}
+	tt.t.Helper()
+	tt.Fatalf("text not equal - see diff:\n%s", diff)
+}
+
+// produceLineDiff returns a line by line diff of expected and got with line numbers. Equal lines are marked with
+// =, removed lines with -, and added lines with +. Only unifiedContext equal lines are shown around changes. The
+// output stops after limit consecutive unequal lines unless limit is 0.
+func produceLineDiff(expected, got []string, limit int) string {
+	ops := alignLines(expected, got)
+	near := make([]bool, len(ops))
+	for k, op := range ops {
+		if op.kind == lineEqual {
+			continue
+		}
+		for n := k - unifiedContext; n <= k+unifiedContext; n++ {
+			if n >= 0 && n < len(ops) {
+				near[n] = true
+			}
+		}
+	}
+	var result []string
+	badCount := 0
+	skipped := false
+	for k, op := range ops {
+		if !near[k] {
+			if !skipped {
+				result = append(result, "...")
+				skipped = true
+			}
+			continue
+		}
+		skipped = false
+		switch op.kind {
+		case lineEqual:
+			result = append(result, fmt.Sprintf(" = %4d %4d | %s", op.e+1, op.g+1, expected[op.e]))
+			badCount = 0
+			continue
+		case lineRemoved:
+			result = append(result, fmt.Sprintf(" - %4d %4s | %s", op.e+1, "", expected[op.e]))
+		case lineAdded:
+			result = append(result, fmt.Sprintf(" + %4s %4d | %s", "", op.g+1, got[op.g]))
+		}
+		badCount++
+		if limit > 0 && badCount > limit {
+			result = append(result, fmt.Sprintf("... stopping after %d unequal lines", limit))
+			break
+		}
+	}
+	return strings.Join(result, "\n")
+}
+
+// normalizeLines returns the lines passed through the normalizers of the tester. A line ending added by a normalizer
+// (i.e. when a trailing CR is converted to LF) is removed since the strings are lines.
+func (tt *tester) normalizeLines(lines []string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		n := NormalizeText(line, tt.normalizers...)
+		if !strings.HasSuffix(line, "\n") {
+			n = strings.TrimSuffix(n, "\n")
+		}
+		result[i] = n
+	}
+	return result
+}
+
+// plainDiffText renders the diffs without colors, marking deletions as [-text-] and insertions as {+text+}
+func plainDiffText(diffs []diffmatchpatch.Diff) string {
+	var b strings.Builder
+	for _, d := range diffs {
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			b.WriteString("[-" + d.Text + "-]")
+		case diffmatchpatch.DiffInsert:
+			b.WriteString("{+" + d.Text + "+}")
+		default:
+			b.WriteString(d.Text)
+		}
 	}
+	return b.String()
 }
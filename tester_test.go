@@ -7,8 +7,10 @@ import (
 )
 
 func TestTester_After(t *testing.T) {
-	t0 := time.Now()
-	t1 := t0.Add(10 * time.Millisecond)
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	t0 := clock.Now()
+	clock.Advance(10 * time.Millisecond)
+	t1 := clock.Now()
 	ensureFailed(t, func(ft *testing.T) {
 		tt := NewTester(ft)
 		tt.CheckAfter(t1, t0)
@@ -26,8 +28,10 @@ func TestTester_After(t *testing.T) {
 }
 
 func TestTester_Before(t *testing.T) {
-	now := time.Now()
-	earlier := now.Add(-10 * time.Millisecond) // 10ms earlier
+	clock := NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	earlier := clock.Now()
+	clock.Advance(10 * time.Millisecond) // 10ms later
+	now := clock.Now()
 	ensureFailed(t, func(ft *testing.T) {
 		tt := NewTester(ft)
 		tt.CheckBefore(earlier, now)
@@ -73,12 +77,27 @@ func Test_produceDiff(t *testing.T) {
 		tt := NewTester(ft)
 		tt.CheckStringSlicesEqual(expected, expected)
 	})
-	// TODO: Check that output is the expected
-	//
-	// diff, ok := produceDiff(expected, got)
-	// if !ok {
-	// 	t.Fatalf("unequal slices - see diff\n%s", diff)
-	// }
+
+	diff, ok := produceDiff([]string{"a", "b", "c", "d"}, []string{"a", "x", "b", "c"}, 0)
+	CheckFalse(ok, t)
+	CheckEqual(strings.Join([]string{
+		" =  e[0] g[0] `a`",
+		" +  g[1] `x`",
+		" =  e[1] g[2] `b`",
+		" =  e[2] g[3] `c`",
+		" -  e[3] `d`",
+	}, "\n"), diff, t)
+
+	diff, ok = produceDiff(expected, got, 0)
+	CheckFalse(ok, t)
+	CheckEqual(strings.Join([]string{
+		" -  e[0] `abc`",
+		" +  g[0] `abcd`",
+		" =  e[1] g[1] `def`",
+		" -  e[2] `xyz`",
+		" +  g[2] `xyza`",
+		" +  g[3] `longer`",
+	}, "\n"), diff, t)
 }
 func Test_CheckTextEqual(t *testing.T) {
 	expected := strings.Join([]string{"abc", "def", "xyz"}, "\n")
@@ -103,3 +122,131 @@ func Test_CheckEqual(t *testing.T) {
 	tt := NewTester(t)
 	tt.CheckNotEqual(1, 2)
 }
+
+func BenchmarkTester_CheckEqual(b *testing.B) {
+	tt := NewTester(b)
+	for i := 0; i < b.N; i++ {
+		tt.At(i).CheckEqual(i, int64(i))
+	}
+}
+
+func FuzzTester_CheckEqual(f *testing.F) {
+	f.Add("abc")
+	f.Fuzz(func(t *testing.T, s string) {
+		NewTester(t).CheckEqual(s, s)
+		CheckNotEqual(s, s+"x", t)
+	})
+}
+
+func TestSoftTester(t *testing.T) {
+	continued := false
+	ensureNotFailed(t, func(ft *testing.T) {
+		// failures are not reported until Flush
+		tt := NewSoftTester(ft)
+		tt.At(1).CheckEqual(1, 2)
+		tt.At(2).CheckTrue(false)
+		continued = true
+	})
+	CheckTrue(continued, t)
+
+	ensureFailed(t, func(ft *testing.T) {
+		tt := NewSoftTester(ft)
+		tt.At(1).CheckEqual(1, 2)
+		tt.Flush()
+	})
+
+	ensureNotFailed(t, func(ft *testing.T) {
+		tt := NewSoftTester(ft)
+		tt.At(1).CheckEqual(1, 1)
+		tt.Flush()
+	})
+
+	tt := &tester{t: t, soft: &softFailures{}}
+	tt.At(1).CheckEqual(1, 2)
+	tt.At(2).CheckNotNil(nil)
+	CheckEqual([]string{"[1] Expected Equal: int 1, got int 2", "[2] Expected: not nil, got nil"}, tt.soft.messages, t)
+}
+
+func TestTester_Run(t *testing.T) {
+	var names []string
+	tt := NewTester(t)
+	tt.At(3)
+	ok := tt.Run("outer", func(ot Tester) {
+		ot.Run("inner", func(it Tester) {
+			it.At(1).CheckEqual(1, 1)
+			names = append(names, it.(*tester).prefix())
+		})
+		names = append(names, ot.(*tester).prefix())
+	})
+	CheckTrue(ok, t)
+	CheckEqual([]string{"outer/inner: [1] ", "outer: "}, names, t)
+	CheckEqual("[3] ", tt.(*tester).prefix(), t)
+}
+
+func TestTester_AtName(t *testing.T) {
+	tt := NewTester(t)
+	CheckEqual("[empty input] ", tt.AtName("empty input").(*tester).prefix(), t)
+	CheckEqual("[2: empty input] ", tt.AtCase(2, "empty input").(*tester).prefix(), t)
+	CheckEqual("[3] ", tt.At(3).(*tester).prefix(), t)
+	CheckEqual("[x] ", tt.AtName("x").(*tester).prefix(), t)
+}
+
+func TestTester_AtMultiple(t *testing.T) {
+	tt := NewTester(t)
+	CheckEqual("[2][5] ", tt.At(2, 5).(*tester).prefix(), t)
+	CheckEqual("", tt.At().(*tester).prefix(), t)
+	ensureFailed(t, func(ft *testing.T) {
+		NewTester(ft).At(1, 2, 3).CheckEqual(1, 2)
+	})
+}
+
+func TestTester_Next(t *testing.T) {
+	tt := NewTester(t)
+	for i := 0; i < 3; i++ {
+		tt.Next()
+		CheckEqual([]int{i}, tt.(*tester).indices, t)
+	}
+	CheckEqual("[2][6] ", tt.At(2, 5).Next().(*tester).prefix(), t)
+	CheckEqual("[1] ", tt.AtCase(0, "x").Next().(*tester).prefix(), t)
+}
+
+func TestTester_Msgf(t *testing.T) {
+	tt := &tester{t: t, soft: &softFailures{}}
+	tt.Msgf("while parsing %q", "100%").At(1).CheckEqual(1, 2)
+	tt.CheckEqual(1, 2)
+	CheckEqual([]string{
+		`[1] Expected Equal: int 1, got int 2 (while parsing "100%")`,
+		`Expected Equal: int 1, got int 2`,
+	}, tt.soft.messages, t)
+}
+
+func TestTester_CheckTextLinesEqual(t *testing.T) {
+	ensureNotFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckTextLinesEqual("a\nb", "a\nb")
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		NewTester(ft).CheckTextLinesEqual("a\nb", "a\nc")
+	})
+
+	expected := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}
+	got := []string{"1", "2", "3", "4", "5", "6", "7", "8", "nine", "10"}
+	CheckEqual(strings.Join([]string{
+		"...",
+		" =    6    6 | 6",
+		" =    7    7 | 7",
+		" =    8    8 | 8",
+		" -    9      | 9",
+		" +         9 | nine",
+		" =   10   10 | 10",
+	}, "\n"), produceLineDiff(expected, got, 0), t)
+}
+
+func TestTester_CheckAll(t *testing.T) {
+	NewTester(t).CheckAll(Pair{"a", 1, int64(1)}, Pair{"b", "x", "x"}, Pair{"err", nil, nil})
+
+	tt := &tester{t: t, soft: &softFailures{}}
+	tt.At(2).CheckAll(Pair{"a", 1, 2}, Pair{"b", "x", "x"}, Pair{Expected: nil, Got: "y"})
+	CheckEqual([]string{
+		"[2] Expected all equal, 2 of 3 differ:\n  a: expected int 1, got int 2\n  #2: expected <nil> <nil>, got string y",
+	}, tt.soft.messages, t)
+}
@@ -0,0 +1,73 @@
+package testutils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// maxTableMismatches is the max number of mismatching cells reported by CheckTextTableEqual
+const maxTableMismatches = 10
+
+var columnSeparator = regexp.MustCompile(`\t+| {2,}`)
+
+// CheckTextTableEqual compares tabular text such as the output of a CLI command cell by cell, ignoring differences
+// in padding. Each non blank line is a row, and a row is split into cells on tabs or on runs of two or more
+// spaces (single spaces are part of the cell). Leading and trailing whitespace of lines and cells is ignored.
+// On failure the row and column (starting at 0) of each mismatching cell is reported.
+func CheckTextTableEqual(expected, got string, t testing.TB) {
+	e := parseTextTable(expected)
+	g := parseTextTable(got)
+	var mismatches []string
+	rows := len(e)
+	if len(g) > rows {
+		rows = len(g)
+	}
+	for r := 0; r < rows && len(mismatches) < maxTableMismatches; r++ {
+		switch {
+		case r >= len(e):
+			mismatches = append(mismatches, fmt.Sprintf("row %d: unexpected row %q", r, g[r]))
+			continue
+		case r >= len(g):
+			mismatches = append(mismatches, fmt.Sprintf("row %d: missing row %q", r, e[r]))
+			continue
+		}
+		cols := len(e[r])
+		if len(g[r]) > cols {
+			cols = len(g[r])
+		}
+		for c := 0; c < cols && len(mismatches) < maxTableMismatches; c++ {
+			var ec, gc string
+			if c < len(e[r]) {
+				ec = e[r][c]
+			}
+			if c < len(g[r]) {
+				gc = g[r][c]
+			}
+			if c >= len(e[r]) || c >= len(g[r]) || ec != gc {
+				mismatches = append(mismatches, fmt.Sprintf("row %d, column %d: expected %q, got %q", r, c, ec, gc))
+			}
+		}
+	}
+	if len(mismatches) > 0 {
+		t.Helper()
+		t.Fatalf("tables not equal:\n%s\nexpected:\n%s\ngot:\n%s", strings.Join(mismatches, "\n"), expected, got)
+	}
+}
+
+func parseTextTable(s string) [][]string {
+	var rows [][]string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		cells := columnSeparator.Split(line, -1)
+		for i, c := range cells {
+			cells[i] = strings.TrimSpace(c)
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
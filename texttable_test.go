@@ -0,0 +1,23 @@
+package testutils
+
+import "testing"
+
+func TestCheckTextTableEqual(t *testing.T) {
+	expected := "NAME\tSTATUS\tAGE\nweb 1\tRunning\t3d\n"
+	ensureNotFailed(t, func(ft *testing.T) {
+		CheckTextTableEqual(expected, "NAME      STATUS     AGE\nweb 1     Running    3d   \n\n", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTextTableEqual(expected, "NAME   STATUS   AGE\nweb 1  Stopped  3d\n", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTextTableEqual(expected, "NAME   STATUS   AGE\n", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckTextTableEqual(expected, "NAME   STATUS\nweb 1  Running  3d\n", ft)
+	})
+}
+
+func Test_parseTextTable(t *testing.T) {
+	CheckEqual([][]string{{"a b", "c"}, {"d", "e", "f"}}, parseTextTable("  a b   c\n\nd\te\t\tf  \n"), t)
+}
@@ -0,0 +1,106 @@
+// Package tracecheck contains an in-memory OpenTelemetry span recorder and checks of the recorded spans, which
+// makes it possible to test the tracing behavior of instrumented code without a collector. It is a separate
+// module to make the github.com/hlindberg/testutils module usable without a dependency on OpenTelemetry.
+package tracecheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Recorder is a TracerProvider that records all spans in memory
+type Recorder struct {
+	*sdktrace.TracerProvider
+	spans *tracetest.SpanRecorder
+}
+
+// NewRecorder returns a new Recorder. The recorder is shut down when the test finishes. Pass the recorder (or
+// the tracers it provides) to the code under test, or install it with otel.SetTracerProvider.
+func NewRecorder(t testing.TB) *Recorder {
+	spans := tracetest.NewSpanRecorder()
+	r := &Recorder{TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)), spans: spans}
+	t.Cleanup(func() { _ = r.Shutdown(context.Background()) })
+	return r
+}
+
+// Spans returns the spans that have ended, in the order they ended
+func (r *Recorder) Spans() []sdktrace.ReadOnlySpan {
+	return r.spans.Ended()
+}
+
+// CheckSpanEmitted checks that the recorder has an ended span with the given name that has all of the given
+// attributes (it may have others). On failure the names and attributes of all ended spans are reported.
+func CheckSpanEmitted(r *Recorder, name string, attrs []attribute.KeyValue, t testing.TB) {
+	for _, s := range r.Spans() {
+		if s.Name() == name && hasAttributes(s, attrs) {
+			return
+		}
+	}
+	t.Helper()
+	t.Fatalf("Expected a span %q with attributes %s, got spans:\n%s", name, formatAttributes(attrs), formatSpans(r.Spans()))
+}
+
+// CheckSpanParentOf checks that the recorder has an ended span with the given child name whose parent is an ended
+// span with the given parent name. On failure the names and parents of all ended spans are reported.
+func CheckSpanParentOf(r *Recorder, parent, child string, t testing.TB) {
+	spans := r.Spans()
+	for _, c := range spans {
+		if c.Name() != child || !c.Parent().IsValid() {
+			continue
+		}
+		for _, p := range spans {
+			if p.Name() == parent && p.SpanContext().SpanID() == c.Parent().SpanID() {
+				return
+			}
+		}
+	}
+	t.Helper()
+	t.Fatalf("Expected span %q to be the parent of span %q, got spans:\n%s", parent, child, formatSpans(spans))
+}
+
+func hasAttributes(s sdktrace.ReadOnlySpan, attrs []attribute.KeyValue) bool {
+	have := attribute.NewSet(s.Attributes()...)
+	for _, a := range attrs {
+		v, ok := have.Value(a.Key)
+		if !ok || v != a.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func formatAttributes(attrs []attribute.KeyValue) string {
+	s := make([]string, len(attrs))
+	for i, a := range attrs {
+		s[i] = fmt.Sprintf("%s=%s", a.Key, a.Value.Emit())
+	}
+	return "{" + strings.Join(s, ", ") + "}"
+}
+
+func formatSpans(spans []sdktrace.ReadOnlySpan) string {
+	if len(spans) == 0 {
+		return "  (none)"
+	}
+	names := map[string]string{}
+	for _, s := range spans {
+		names[s.SpanContext().SpanID().String()] = s.Name()
+	}
+	lines := make([]string, len(spans))
+	for i, s := range spans {
+		lines[i] = fmt.Sprintf("  %q %s", s.Name(), formatAttributes(s.Attributes()))
+		if s.Parent().IsValid() {
+			parent, ok := names[s.Parent().SpanID().String()]
+			if !ok {
+				parent = s.Parent().SpanID().String()
+			}
+			lines[i] += fmt.Sprintf(" parent %q", parent)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,66 @@
+package tracecheck
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// checkEqual is a minimal CheckEqual, since this module does not depend on github.com/hlindberg/testutils
+func checkEqual(expected, got interface{}, t *testing.T) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("Expected Equal: %T %v, got %T %v", expected, expected, got, got)
+	}
+}
+
+// messageTB records the messages of Fatalf
+type messageTB struct {
+	testing.TB
+	messages []string
+}
+
+func (m *messageTB) Helper() {}
+
+func (m *messageTB) Fatalf(format string, args ...interface{}) {
+	m.messages = append(m.messages, fmt.Sprintf(format, args...))
+}
+
+func handle(tp trace.TracerProvider) {
+	tracer := tp.Tracer("test")
+	ctx, span := tracer.Start(context.Background(), "request", trace.WithAttributes(attribute.String("method", "GET"), attribute.Int("size", 3)))
+	_, child := tracer.Start(ctx, "query")
+	child.End()
+	span.End()
+}
+
+func TestCheckSpanEmitted(t *testing.T) {
+	r := NewRecorder(t)
+	handle(r)
+	CheckSpanEmitted(r, "request", []attribute.KeyValue{attribute.String("method", "GET")}, t)
+	CheckSpanEmitted(r, "query", nil, t)
+
+	m := &messageTB{}
+	CheckSpanEmitted(r, "request", []attribute.KeyValue{attribute.String("method", "POST")}, m)
+	CheckSpanEmitted(r, "request", []attribute.KeyValue{attribute.String("path", "/")}, m)
+	CheckSpanEmitted(r, "missing", nil, m)
+	checkEqual(3, len(m.messages), t)
+	checkEqual("Expected a span \"missing\" with attributes {}, got spans:\n"+
+		"  \"query\" {} parent \"request\"\n  \"request\" {method=GET, size=3}", m.messages[2], t)
+}
+
+func TestCheckSpanParentOf(t *testing.T) {
+	r := NewRecorder(t)
+	handle(r)
+	CheckSpanParentOf(r, "request", "query", t)
+
+	m := &messageTB{}
+	CheckSpanParentOf(r, "query", "request", m)
+	CheckSpanParentOf(r, "other", "query", m)
+	CheckSpanParentOf(NewRecorder(t), "request", "query", m)
+	checkEqual(3, len(m.messages), t)
+}
@@ -0,0 +1,78 @@
+package testutils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// unifiedContext is the number of equal lines shown around changes in a unified diff
+const unifiedContext = 3
+
+// unifiedDiff returns a unified diff (as produced by `diff -u`) that transforms expected into got, with the
+// file names "expected" and "got". An empty string is returned if there are no differences.
+func unifiedDiff(expected, got []string) string {
+	ops := alignLines(expected, got)
+
+	// ePos[k] and gPos[k] are the number of lines of expected and got before op k
+	ePos := make([]int, len(ops)+1)
+	gPos := make([]int, len(ops)+1)
+	var changes []int
+	for k, op := range ops {
+		ePos[k+1], gPos[k+1] = ePos[k], gPos[k]
+		if op.kind != lineAdded {
+			ePos[k+1]++
+		}
+		if op.kind != lineRemoved {
+			gPos[k+1]++
+		}
+		if op.kind != lineEqual {
+			changes = append(changes, k)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	out := []string{"--- expected", "+++ got"}
+	for c := 0; c < len(changes); {
+		// extend the hunk while the next change is close enough for the contexts to overlap
+		last := c
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*unifiedContext+1 {
+			last++
+		}
+		start := changes[c] - unifiedContext
+		if start < 0 {
+			start = 0
+		}
+		end := changes[last] + unifiedContext + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+		out = append(out, fmt.Sprintf("@@ -%s +%s @@", hunkRange(ePos[start], ePos[end]), hunkRange(gPos[start], gPos[end])))
+		for _, op := range ops[start:end] {
+			switch op.kind {
+			case lineEqual:
+				out = append(out, " "+expected[op.e])
+			case lineRemoved:
+				out = append(out, "-"+expected[op.e])
+			case lineAdded:
+				out = append(out, "+"+got[op.g])
+			}
+		}
+		c = last + 1
+	}
+	return strings.Join(out, "\n")
+}
+
+// hunkRange formats the range of lines from (0 based, inclusive) to (exclusive) as a unified diff range
+func hunkRange(from, to int) string {
+	count := to - from
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", from)
+	case 1:
+		return fmt.Sprintf("%d", from+1)
+	default:
+		return fmt.Sprintf("%d,%d", from+1, count)
+	}
+}
@@ -0,0 +1,43 @@
+package testutils
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_unifiedDiff(t *testing.T) {
+	CheckEqual("", unifiedDiff([]string{"a"}, []string{"a"}), t)
+
+	expected := strings.Split("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12", "\n")
+	got := strings.Split("1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13", "\n")
+	CheckEqual(strings.Join([]string{
+		"--- expected",
+		"+++ got",
+		"@@ -1,6 +1,6 @@",
+		" 1",
+		" 2",
+		"-3",
+		"+three",
+		" 4",
+		" 5",
+		" 6",
+		"@@ -10,3 +10,4 @@",
+		" 10",
+		" 11",
+		" 12",
+		"+13",
+	}, "\n"), unifiedDiff(expected, got), t)
+
+	CheckEqual("--- expected\n+++ got\n@@ -0,0 +1 @@\n+a", unifiedDiff(nil, []string{"a"}), t)
+}
+
+func TestUnifiedDiffOption(t *testing.T) {
+	tt := NewTesterWith(t, UnifiedDiff()).(*tester)
+	tt.soft = &softFailures{}
+	tt.CheckTextEqual("a\nb", "a\nc")
+	tt.CheckStringSlicesEqual([]string{"a"}, []string{"b"})
+	CheckEqual([]string{
+		"strings not equal - see diff:\n--- expected\n+++ got\n@@ -1,2 +1,2 @@\n a\n-b\n+c",
+		"slices not equal - see diff:\n--- expected\n+++ got\n@@ -1 +1 @@\n-a\n+b",
+	}, tt.soft.messages, t)
+}
@@ -0,0 +1,151 @@
+package testutils
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// firstDifference compares the two values and returns a description of the path to the first difference found,
+// and true if there is a difference. The path starts with the given root, and is extended with `.field`, `[index]`,
+// and `[key]` as the values are traversed.
+//
+// In contrast to reflect.DeepEqual, time.Time values are compared with Equal, and unexported fields are compared
+// as well (which also means that this function works on values that cannot be turned into an interface{}). As in
+// reflect.DeepEqual, func values are only equal if both are nil, and cyclic values are handled by considering a
+// pair of pointers, maps, or slices that is already being compared as equal.
+func firstDifference(a, b reflect.Value, root string) (string, bool) {
+	return diffValues(a, b, root, diffOptions{})
+}
+
+// diffOptions modify how diffValues compares values
+type diffOptions struct {
+	// nilEqualsEmpty makes a nil slice or map equal to an empty slice or map of the same type
+	nilEqualsEmpty bool
+	// sameLocation makes time.Time values that are the same instant differ if their locations differ
+	sameLocation bool
+	// locationNotes, if not nil, is appended with a note for each pair of equal time.Time values in different
+	// locations
+	locationNotes *[]string
+	// visited holds the pairs of pointers, maps, and slices being compared. It is created by diffValues if nil.
+	visited map[diffVisit]bool
+}
+
+// diffVisit is a pair of pointers, maps, or slices of the same type being compared by diffValues
+type diffVisit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+// seen returns true if the pair of non nil pointers, maps, or slices is already being compared, and otherwise
+// records that it is
+func (opts diffOptions) seen(a, b reflect.Value) bool {
+	v := diffVisit{a.Pointer(), b.Pointer(), a.Type()}
+	if opts.visited[v] {
+		return true
+	}
+	opts.visited[v] = true
+	return false
+}
+
+func diffValues(a, b reflect.Value, path string, opts diffOptions) (string, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			return path, true
+		}
+		return "", false
+	}
+	if a.Type() != b.Type() {
+		return path, true
+	}
+	if opts.visited == nil {
+		opts.visited = map[diffVisit]bool{}
+	}
+
+	switch a.Kind() {
+	case reflect.Bool:
+		return path, a.Bool() != b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return path, a.Int() != b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return path, a.Uint() != b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return path, a.Float() != b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return path, a.Complex() != b.Complex()
+	case reflect.String:
+		return path, a.String() != b.String()
+	case reflect.Chan, reflect.UnsafePointer:
+		return path, a.Pointer() != b.Pointer()
+	case reflect.Func:
+		return path, !a.IsNil() || !b.IsNil()
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return path, a.IsNil() != b.IsNil()
+		}
+		if a.Kind() == reflect.Ptr && (a.Pointer() == b.Pointer() || opts.seen(a, b)) {
+			return "", false
+		}
+		return diffValues(a.Elem(), b.Elem(), path, opts)
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if p, ok := diffValues(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i), opts); ok {
+				return p, true
+			}
+		}
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() && !opts.nilEqualsEmpty || a.Len() != b.Len() {
+			return path, true
+		}
+		if a.Len() == 0 || opts.seen(a, b) {
+			return "", false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if p, ok := diffValues(a.Index(i), b.Index(i), fmt.Sprintf("%s[%d]", path, i), opts); ok {
+				return p, true
+			}
+		}
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() && !opts.nilEqualsEmpty || a.Len() != b.Len() {
+			return path, true
+		}
+		if a.Len() == 0 || opts.seen(a, b) {
+			return "", false
+		}
+		for _, k := range a.MapKeys() {
+			kp := fmt.Sprintf("%s[%v]", path, k)
+			bv := b.MapIndex(k)
+			if !bv.IsValid() {
+				return kp, true
+			}
+			if p, ok := diffValues(a.MapIndex(k), bv, kp, opts); ok {
+				return p, true
+			}
+		}
+	case reflect.Struct:
+		if a.Type() == timeType && a.CanInterface() && b.CanInterface() {
+			at, bt := a.Interface().(time.Time), b.Interface().(time.Time)
+			if !at.Equal(bt) {
+				return path, true
+			}
+			if al, bl := at.Location().String(), bt.Location().String(); al != bl {
+				if opts.sameLocation {
+					return path, true
+				}
+				if opts.locationNotes != nil {
+					*opts.locationNotes = append(*opts.locationNotes, fmt.Sprintf("%s: same instant in locations %s and %s", path, al, bl))
+				}
+			}
+			return "", false
+		}
+		for i := 0; i < a.NumField(); i++ {
+			fp := path + "." + a.Type().Field(i).Name
+			if p, ok := diffValues(a.Field(i), b.Field(i), fp, opts); ok {
+				return p, true
+			}
+		}
+	}
+	return "", false
+}
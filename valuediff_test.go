@@ -0,0 +1,88 @@
+package testutils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_firstDifference(t *testing.T) {
+	type inner struct {
+		s []string
+	}
+	type outer struct {
+		A int
+		B map[string]inner
+	}
+	a := outer{A: 1, B: map[string]inner{"x": {s: []string{"a", "b"}}}}
+	b := outer{A: 1, B: map[string]inner{"x": {s: []string{"a", "c"}}}}
+	path, ok := firstDifference(reflect.ValueOf(a), reflect.ValueOf(b), "v")
+	CheckTrue(ok, t)
+	CheckEqual("v.B[x].s[1]", path, t)
+
+	_, ok = firstDifference(reflect.ValueOf(a), reflect.ValueOf(a), "v")
+	CheckFalse(ok, t)
+}
+
+func Test_diffValuesTimeLocation(t *testing.T) {
+	type event struct {
+		At []time.Time
+	}
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cet := utc.In(time.FixedZone("CET", 3600))
+	a, b := reflect.ValueOf(event{At: []time.Time{utc}}), reflect.ValueOf(event{At: []time.Time{cet}})
+
+	var notes []string
+	_, ok := diffValues(a, b, "v", diffOptions{locationNotes: &notes})
+	CheckFalse(ok, t)
+	CheckEqual([]string{"v.At[0]: same instant in locations UTC and CET"}, notes, t)
+
+	path, ok := diffValues(a, b, "v", diffOptions{sameLocation: true})
+	CheckTrue(ok, t)
+	CheckEqual("v.At[0]", path, t)
+}
+
+type diffNode struct {
+	V    int
+	Next *diffNode
+}
+
+// diffList returns a linked list with the values 0 to n-1, where the value of the last node is last
+func diffList(n, last int) *diffNode {
+	l := &diffNode{V: last}
+	for i := n - 2; i >= 0; i-- {
+		l = &diffNode{V: i, Next: l}
+	}
+	return l
+}
+
+func Test_diffValuesDeep(t *testing.T) {
+	path, ok := firstDifference(reflect.ValueOf(diffList(150, 149)), reflect.ValueOf(diffList(150, 0)), "v")
+	CheckTrue(ok, t)
+	CheckEqual("v"+strings.Repeat(".Next", 149)+".V", path, t)
+	_, ok = firstDifference(reflect.ValueOf(diffList(150, 149)), reflect.ValueOf(diffList(150, 149)), "v")
+	CheckFalse(ok, t)
+}
+
+func Test_diffValuesCyclic(t *testing.T) {
+	cycle := func(v int) *diffNode {
+		a := &diffNode{V: 1}
+		a.Next = &diffNode{V: v, Next: a}
+		return a
+	}
+	_, ok := firstDifference(reflect.ValueOf(cycle(2)), reflect.ValueOf(cycle(2)), "v")
+	CheckFalse(ok, t)
+	path, ok := firstDifference(reflect.ValueOf(cycle(2)), reflect.ValueOf(cycle(3)), "v")
+	CheckTrue(ok, t)
+	CheckEqual("v.Next.V", path, t)
+}
+
+func Test_diffValuesFunc(t *testing.T) {
+	f := func() {}
+	_, ok := firstDifference(reflect.ValueOf(f), reflect.ValueOf(f), "v")
+	CheckTrue(ok, t)
+	var nf func()
+	_, ok = firstDifference(reflect.ValueOf(nf), reflect.ValueOf(nf), "v")
+	CheckFalse(ok, t)
+}
@@ -0,0 +1,165 @@
+package testutils
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// CheckXMLEqual checks that the expected and got XML documents are equal after both have been canonicalized:
+// the order of attributes does not matter, whitespace between elements and around text is ignored, namespace
+// prefixes are replaced by the namespaces they refer to, and comments and processing instructions are ignored.
+// On failure the XPath of the first differing node is reported.
+func CheckXMLEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	e, err := parseXML(expected)
+	if err != nil {
+		t.Fatalf("CheckXMLEqual: expected is not valid XML: %s", err.Error())
+		return
+	}
+	g, err := parseXML(got)
+	if err != nil {
+		t.Fatalf("Expected valid XML, got error: %s", err.Error())
+		return
+	}
+	if path, reason := xmlDiff(e, g, ""); path != "" {
+		t.Fatalf("Expected equal XML, but at %s %s", path, reason)
+	}
+}
+
+// xmlNode is a canonicalized element or (when name is empty) a text node
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	text     string
+	children []*xmlNode
+}
+
+// parseXML returns a root node with the top level elements of the document as children
+func parseXML(s string) (*xmlNode, error) {
+	d := xml.NewDecoder(bytes.NewReader([]byte(s)))
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			if len(stack) > 1 {
+				return nil, io.ErrUnexpectedEOF
+			}
+			return root, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		top := stack[len(stack)-1]
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: tok.Name}
+			for _, a := range tok.Attr {
+				if a.Name.Space != "xmlns" && !(a.Name.Space == "" && a.Name.Local == "xmlns") {
+					n.attrs = append(n.attrs, a)
+				}
+			}
+			sort.Slice(n.attrs, func(i, j int) bool {
+				a, b := n.attrs[i].Name, n.attrs[j].Name
+				return a.Space < b.Space || a.Space == b.Space && a.Local < b.Local
+			})
+			top.children = append(top.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if text := strings.TrimSpace(string(tok)); text != "" {
+				// adjacent text (i.e. separated by a comment) is joined
+				if k := len(top.children); k > 0 && top.children[k-1].name.Local == "" {
+					top.children[k-1].text += text
+				} else {
+					top.children = append(top.children, &xmlNode{text: text})
+				}
+			}
+		}
+	}
+}
+
+// xmlDiff returns the XPath of the first difference between the children of the two nodes, and the reason, or an
+// empty path if they are equal. The given path is the XPath of the nodes.
+func xmlDiff(e, g *xmlNode, path string) (string, string) {
+	for _, a := range e.attrs {
+		ap := path + "/@" + xmlName(a.Name)
+		ga, ok := xmlAttr(g, a.Name)
+		if !ok {
+			return ap, "is missing"
+		}
+		if ga != a.Value {
+			return ap, fmt.Sprintf("expected %q, got %q", a.Value, ga)
+		}
+	}
+	for _, a := range g.attrs {
+		if _, ok := xmlAttr(e, a.Name); !ok {
+			return path + "/@" + xmlName(a.Name), "is unexpected"
+		}
+	}
+	counts := map[xml.Name]int{}
+	for i, ec := range e.children {
+		cp := path + "/" + xmlStep(ec, counts)
+		if i >= len(g.children) {
+			return cp, "is missing"
+		}
+		gc := g.children[i]
+		if ec.name != gc.name {
+			return cp, fmt.Sprintf("expected %s, got %s", xmlNodeText(ec), xmlNodeText(gc))
+		}
+		if ec.name.Local == "" {
+			if ec.text != gc.text {
+				return cp, fmt.Sprintf("expected %q, got %q", ec.text, gc.text)
+			}
+			continue
+		}
+		if p, reason := xmlDiff(ec, gc, cp); p != "" {
+			return p, reason
+		}
+	}
+	if len(g.children) > len(e.children) {
+		gc := g.children[len(e.children)]
+		return path + "/" + xmlStep(gc, counts), fmt.Sprintf("got unexpected %s", xmlNodeText(gc))
+	}
+	return "", ""
+}
+
+func xmlAttr(n *xmlNode, name xml.Name) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// xmlStep returns the XPath step for the node, i.e. `name[position]` or `text()[position]`, where position counts
+// the siblings of the same kind. The counts are updated.
+func xmlStep(n *xmlNode, counts map[xml.Name]int) string {
+	counts[n.name]++
+	if n.name.Local == "" {
+		return fmt.Sprintf("text()[%d]", counts[n.name])
+	}
+	return fmt.Sprintf("%s[%d]", xmlName(n.name), counts[n.name])
+}
+
+func xmlName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return "{" + n.Space + "}" + n.Local
+}
+
+func xmlNodeText(n *xmlNode) string {
+	if n.name.Local == "" {
+		return fmt.Sprintf("text %q", n.text)
+	}
+	return "element " + xmlName(n.name)
+}
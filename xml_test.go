@@ -0,0 +1,44 @@
+package testutils
+
+import "testing"
+
+func TestCheckXMLEqual(t *testing.T) {
+	expected := `<a:root xmlns:a="urn:x" id="1" kind="k"><item>one</item><item>two</item></a:root>`
+	CheckXMLEqual(expected, `<?xml version="1.0"?>
+<b:root kind="k" id="1" xmlns:b="urn:x">
+  <!-- items -->
+  <item> one </item>
+  <item>two</item>
+</b:root>`, t)
+
+	for _, got := range []string{
+		`<root xmlns="urn:y" id="1" kind="k"><item>one</item><item>two</item></root>`,
+		`<a:root xmlns:a="urn:x" id="2" kind="k"><item>one</item><item>two</item></a:root>`,
+		`<a:root xmlns:a="urn:x" kind="k"><item>one</item><item>two</item></a:root>`,
+		`<a:root xmlns:a="urn:x" id="1" kind="k" x="y"><item>one</item><item>two</item></a:root>`,
+		`<a:root xmlns:a="urn:x" id="1" kind="k"><item>one</item></a:root>`,
+		`<a:root xmlns:a="urn:x" id="1" kind="k"><item>one</item><item>two</item><item/></a:root>`,
+		`<a:root xmlns:a="urn:x" id="1" kind="k"><item>one</item><item>three</item></a:root>`,
+		`<a:root xmlns:a="urn:x" id="1" kind="k"><item>one</item><other>two</other></a:root>`,
+		`<a:root xmlns:a="urn:x" id="1" kind="k"><item>one</item>`,
+	} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckXMLEqual(expected, got, ft)
+		})
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckXMLEqual("<a>", "<a/>", ft)
+	})
+}
+
+func Test_xmlDiff_path(t *testing.T) {
+	e, _ := parseXML(`<root><item/><item><name x="1">a</name></item></root>`)
+	g, _ := parseXML(`<root><item/><item><name x="2">a</name></item></root>`)
+	path, reason := xmlDiff(e, g, "")
+	CheckEqual("/root[1]/item[2]/name[1]/@x", path, t)
+	CheckEqual(`expected "1", got "2"`, reason, t)
+
+	g, _ = parseXML(`<root><item/><item><name x="1">b</name></item></root>`)
+	path, _ = xmlDiff(e, g, "")
+	CheckEqual("/root[1]/item[2]/name[1]/text()[1]", path, t)
+}
@@ -0,0 +1,173 @@
+package testutils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CheckYAMLEqual checks that the expected and got YAML texts are semantically equal. Both texts are parsed, and the
+// resulting node trees are compared: mappings are equal when they have the same keys with equal values
+// irrespective of order, sequences are compared element by element, and scalars are compared by their resolved
+// values (so `1` and `0x1` are equal, but `1` and `"1"` are not). Aliases are compared by the content of the anchor
+// they refer to, and comments and styles are ignored. A text may contain multiple documents. On failure the path
+// to the first difference is reported.
+func CheckYAMLEqual(expected, got string, t testing.TB) {
+	t.Helper()
+	e, err := parseYAML(expected)
+	if err != nil {
+		t.Fatalf("CheckYAMLEqual: expected is not valid YAML: %s", err.Error())
+		return
+	}
+	g, err := parseYAML(got)
+	if err != nil {
+		t.Fatalf("Expected valid YAML, got error: %s", err.Error())
+		return
+	}
+	if len(e) != len(g) {
+		t.Fatalf("Expected %d YAML documents, got %d", len(e), len(g))
+		return
+	}
+	for i := range e {
+		root := "$"
+		if len(e) > 1 {
+			root = fmt.Sprintf("document[%d]", i)
+		}
+		if path, reason := yamlDiff(e[i], g[i], root); path != "" {
+			t.Fatalf("Expected equal YAML, but at %s %s", path, reason)
+			return
+		}
+	}
+}
+
+// parseYAML returns the root nodes of all documents in the given text
+func parseYAML(s string) ([]*yaml.Node, error) {
+	d := yaml.NewDecoder(bytes.NewReader([]byte(s)))
+	var docs []*yaml.Node
+	for {
+		var n yaml.Node
+		err := d.Decode(&n)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, &n)
+	}
+}
+
+// yamlDiff returns the path to the first difference between the two nodes and the reason, or an empty path if
+// the nodes are equal
+func yamlDiff(e, g *yaml.Node, path string) (string, string) {
+	e, g = yamlContent(e), yamlContent(g)
+	if e.Kind != g.Kind {
+		return path, fmt.Sprintf("expected a %s, got a %s", yamlKind(e), yamlKind(g))
+	}
+	switch e.Kind {
+	case yaml.MappingNode:
+		ekeys, em := yamlMapping(e)
+		_, gm := yamlMapping(g)
+		if len(em) != len(gm) {
+			return path, fmt.Sprintf("expected a mapping with %d keys, got %d", len(em), len(gm))
+		}
+		for _, k := range ekeys {
+			kp := path + "." + k
+			gv, ok := gm[k]
+			if !ok {
+				return kp, "is missing"
+			}
+			if p, reason := yamlDiff(em[k], gv, kp); p != "" {
+				return p, reason
+			}
+		}
+	case yaml.SequenceNode:
+		if len(e.Content) != len(g.Content) {
+			return path, fmt.Sprintf("expected a sequence of length %d, got %d", len(e.Content), len(g.Content))
+		}
+		for i := range e.Content {
+			if p, reason := yamlDiff(e.Content[i], g.Content[i], fmt.Sprintf("%s[%d]", path, i)); p != "" {
+				return p, reason
+			}
+		}
+	case yaml.ScalarNode:
+		var ev, gv interface{}
+		eErr, gErr := e.Decode(&ev), g.Decode(&gv)
+		if eErr != nil || gErr != nil || !reflect.DeepEqual(ev, gv) {
+			return path, fmt.Sprintf("expected %s, got %s", e.Value, g.Value)
+		}
+	}
+	return "", ""
+}
+
+// yamlContent returns the node a document or alias node stands for
+func yamlContent(n *yaml.Node) *yaml.Node {
+	for {
+		switch {
+		case n.Kind == yaml.DocumentNode && len(n.Content) == 1:
+			n = n.Content[0]
+		case n.Kind == yaml.AliasNode && n.Alias != nil:
+			n = n.Alias
+		default:
+			return n
+		}
+	}
+}
+
+// yamlMapping returns the keys of a mapping node in order, and its values keyed by the values of its keys. The
+// entries of the mappings referenced by merge keys (`<<: *base`) are included, with local keys overriding merged
+// ones, and the entries of earlier mappings in a merged sequence overriding those of later ones.
+func yamlMapping(n *yaml.Node) ([]string, map[string]*yaml.Node) {
+	var keys []string
+	m := make(map[string]*yaml.Node, len(n.Content)/2)
+	set := func(k string, v *yaml.Node, merged bool) {
+		if _, ok := m[k]; !ok {
+			keys = append(keys, k)
+		} else if merged {
+			return
+		}
+		m[k] = v
+	}
+	merge := func(v *yaml.Node) {
+		if v = yamlContent(v); v.Kind == yaml.MappingNode {
+			mk, mm := yamlMapping(v)
+			for _, k := range mk {
+				set(k, mm[k], true)
+			}
+		}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := yamlContent(n.Content[i]), n.Content[i+1]
+		if k.Kind != yaml.ScalarNode || k.ShortTag() != "!!merge" {
+			set(k.Value, v, false)
+			continue
+		}
+		if sv := yamlContent(v); sv.Kind == yaml.SequenceNode {
+			for _, e := range sv.Content {
+				merge(e)
+			}
+		} else {
+			merge(v)
+		}
+	}
+	return keys, m
+}
+
+func yamlKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		return "document"
+	case yaml.SequenceNode:
+		return "sequence"
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.ScalarNode:
+		return "scalar"
+	}
+	return "node"
+}
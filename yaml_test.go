@@ -0,0 +1,49 @@
+package testutils
+
+import "testing"
+
+func TestCheckYAMLEqual(t *testing.T) {
+	CheckYAMLEqual("a: 1\nb: [x, y]\n", "# comment\nb:\n  - x\n  - 'y'\na: 0x1\n", t)
+	CheckYAMLEqual("base: &b {x: 1}\nother: *b\n", "base: {x: 1}\nother: {x: 1}\n", t)
+	CheckYAMLEqual("a: 1\n---\nb: 2\n", "a: 1\n---\nb: 2\n", t)
+	CheckYAMLEqual("base: &b {x: 1, y: 2}\nother:\n  <<: *b\n  y: 3\n", "base: {x: 1, y: 2}\nother: {x: 1, y: 3}\n", t)
+	CheckYAMLEqual("a: &a {x: 1}\nb: &b {x: 2, y: 2}\nc: {<<: [*a, *b], z: 3}\n", "a: {x: 1}\nb: {x: 2, y: 2}\nc: {x: 1, y: 2, z: 3}\n", t)
+	CheckYAMLEqual("", "", t)
+
+	for _, got := range []string{
+		"a: 1\nb: [x]\n",
+		"a: '1'\nb: [x, y]\n",
+		"a: 1\nc: [x, y]\n",
+		"a: 1\nb: {x: y}\n",
+		"a: 1\nb: [x, y]\nc: 3\n",
+		"a: 1\n---\nb: [x, y]\n",
+		"a: [",
+	} {
+		ensureFailed(t, func(ft *testing.T) {
+			CheckYAMLEqual("a: 1\nb: [x, y]\n", got, ft)
+		})
+	}
+	ensureFailed(t, func(ft *testing.T) {
+		CheckYAMLEqual("a: [", "a: 1", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckYAMLEqual("base: &b {x: 1}\nother: {<<: *b}\n", "base: {x: 1}\nother: {x: 1, y: 2}\n", ft)
+	})
+	ensureFailed(t, func(ft *testing.T) {
+		CheckYAMLEqual("other: {'<<': {x: 1}}\n", "other: {x: 1}\n", ft)
+	})
+}
+
+func Test_yamlDiff_path(t *testing.T) {
+	e, _ := parseYAML("a:\n  b: [1, {c: true}]\n")
+	g, _ := parseYAML("a:\n  b: [1, {c: false}]\n")
+	path, reason := yamlDiff(e[0], g[0], "$")
+	CheckEqual("$.a.b[1].c", path, t)
+	CheckEqual("expected true, got false", reason, t)
+
+	e, _ = parseYAML("base: &b {x: 1}\nother: {<<: *b, y: 2}\n")
+	g, _ = parseYAML("base: {x: 1}\nother: {x: 2, y: 2}\n")
+	path, reason = yamlDiff(e[0], g[0], "$")
+	CheckEqual("$.other.x", path, t)
+	CheckEqual("expected 1, got 2", reason, t)
+}